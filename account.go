@@ -9,9 +9,9 @@ type AccountInfo struct {
 }
 
 // GetAccountInfo 获取用户信息
-func (c *Client) GetAccountInfo(ctx context.Context) (*AccountInfo, error) {
+func (c *Client) GetAccountInfo(ctx context.Context, opts ...CallOption) (*AccountInfo, error) {
 	var result AccountInfo
-	err := c.Request(ctx, MethodGetAccountInfo, nil, &result)
+	err := c.Request(ctx, MethodGetAccountInfo, nil, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
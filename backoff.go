@@ -0,0 +1,54 @@
+package fulu_gosdk
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff 指数退避策略
+type Backoff struct {
+	Initial    time.Duration // 首次等待时间
+	Max        time.Duration // 最大等待时间
+	Multiplier float64       // 增长倍数
+	Jitter     float64       // 随机抖动比例，取值0~1
+}
+
+// DefaultBackoff 默认退避策略
+var DefaultBackoff = Backoff{
+	Initial:    500 * time.Millisecond,
+	Max:        10 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.2,
+}
+
+// Delay 第attempt次(从0开始)重试前的等待时间
+func (b Backoff) Delay(attempt int) time.Duration {
+	if b.Initial <= 0 {
+		b.Initial = DefaultBackoff.Initial
+	}
+	if b.Multiplier < 1 {
+		b.Multiplier = 1
+	}
+	var delay = float64(b.Initial) * math.Pow(b.Multiplier, float64(attempt))
+	if b.Max > 0 && delay > float64(b.Max) {
+		delay = float64(b.Max)
+	}
+	if b.Jitter > 0 {
+		jitter := math.Min(b.Jitter, 1)
+		delay = delay * (1 - jitter + 2*jitter*rand.Float64())
+	}
+	return time.Duration(delay)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,215 @@
+package fulu_gosdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen 熔断器打开，请求未发出
+var ErrCircuitOpen = errors.New("fulu: circuit breaker is open")
+
+// CircuitState 熔断器状态
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // 关闭，正常请求
+	CircuitOpen                         // 打开，快速失败
+	CircuitHalfOpen                     // 半开，放行探测请求
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig 熔断配置，按接口分别统计
+type CircuitBreakerConfig struct {
+	FailureThreshold int           // 连续失败多少次后打开
+	OpenTimeout      time.Duration // 打开后多久进入半开状态
+	HalfOpenRequests int           // 半开状态下允许的探测请求数，全部成功后关闭
+	// OnStateChange 状态变更回调，在锁外同步调用
+	OnStateChange func(method Method, from, to CircuitState)
+}
+
+// DefaultCircuitBreakerConfig 默认熔断配置
+var DefaultCircuitBreakerConfig = CircuitBreakerConfig{
+	FailureThreshold: 5,
+	OpenTimeout:      30 * time.Second,
+	HalfOpenRequests: 1,
+}
+
+type circuitBreaker struct {
+	cfg      CircuitBreakerConfig
+	mu       sync.Mutex
+	circuits map[Method]*circuit
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	inflight int    // 半开状态下未完成的探测请求
+	success  int    // 半开状态下成功的探测请求
+	probe    uint64 // 进入半开状态的次数，用于识别本轮的探测请求
+}
+
+func newCircuitBreaker(cfg CircuitBreakerConfig) *circuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = DefaultCircuitBreakerConfig.FailureThreshold
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = DefaultCircuitBreakerConfig.OpenTimeout
+	}
+	if cfg.HalfOpenRequests <= 0 {
+		cfg.HalfOpenRequests = DefaultCircuitBreakerConfig.HalfOpenRequests
+	}
+	return &circuitBreaker{cfg: cfg, circuits: make(map[Method]*circuit)}
+}
+
+func (b *circuitBreaker) State(method Method) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.circuits[method]; ok {
+		if c.state == CircuitOpen && time.Since(c.openedAt) >= b.cfg.OpenTimeout {
+			return CircuitHalfOpen
+		}
+		return c.state
+	}
+	return CircuitClosed
+}
+
+// allow 判断请求是否放行，半开状态下放行的探测请求返回本轮的探测编号，否则为0
+func (b *circuitBreaker) allow(method Method) (uint64, error) {
+	b.mu.Lock()
+	c, ok := b.circuits[method]
+	if !ok {
+		c = &circuit{}
+		b.circuits[method] = c
+	}
+	var from = c.state
+	switch c.state {
+	case CircuitOpen:
+		if time.Since(c.openedAt) < b.cfg.OpenTimeout {
+			b.mu.Unlock()
+			return 0, ErrCircuitOpen
+		}
+		c.state = CircuitHalfOpen
+		c.inflight = 0
+		c.success = 0
+		c.probe++
+		fallthrough
+	case CircuitHalfOpen:
+		if c.inflight+c.success >= b.cfg.HalfOpenRequests {
+			var to = c.state
+			b.mu.Unlock()
+			b.notify(method, from, to)
+			return 0, ErrCircuitOpen
+		}
+		c.inflight++
+		var (
+			probe = c.probe
+			to    = c.state
+		)
+		b.mu.Unlock()
+		b.notify(method, from, to)
+		return probe, nil
+	}
+	b.mu.Unlock()
+	return 0, nil
+}
+
+// done 记录请求结果，半开状态下只统计本轮放行的探测请求，
+// 熔断前发出的请求在半开状态下完成时不影响状态
+func (b *circuitBreaker) done(method Method, probe uint64, failed bool) {
+	b.mu.Lock()
+	c := b.circuits[method]
+	var from = c.state
+	switch {
+	case c.state == CircuitClosed:
+		if !failed {
+			c.failures = 0
+			break
+		}
+		c.failures++
+		if c.failures >= b.cfg.FailureThreshold {
+			c.state = CircuitOpen
+			c.openedAt = time.Now()
+		}
+	case c.state == CircuitHalfOpen && probe == c.probe:
+		c.inflight--
+		if failed {
+			c.state = CircuitOpen
+			c.openedAt = time.Now()
+			break
+		}
+		c.success++
+		if c.success >= b.cfg.HalfOpenRequests {
+			c.state = CircuitClosed
+			c.failures = 0
+		}
+	}
+	var to = c.state
+	b.mu.Unlock()
+	b.notify(method, from, to)
+}
+
+func (b *circuitBreaker) notify(method Method, from, to CircuitState) {
+	if from != to && b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(method, from, to)
+	}
+}
+
+// CircuitState 获取接口当前熔断状态，未开启熔断时始终为CircuitClosed
+func (c *Client) CircuitState(method Method) CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	return c.breaker.State(method)
+}
+
+func (b *circuitBreaker) interceptor(ctx context.Context, call *Call, next Invoker) error {
+	probe, err := b.allow(call.Method)
+	if err != nil {
+		return err
+	}
+	err = next(ctx, call)
+	if errors.Is(ctx.Err(), context.Canceled) {
+		// 调用方取消的请求不计入统计
+		b.release(call.Method, probe)
+		return err
+	}
+	b.done(call.Method, probe, isGatewayFailure(err))
+	return err
+}
+
+// release 归还未完成的探测请求名额
+func (b *circuitBreaker) release(method Method, probe uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c := b.circuits[method]; c.state == CircuitHalfOpen && probe == c.probe {
+		c.inflight--
+	}
+}
+
+// isGatewayFailure 网络错误及5xx响应计为失败，福禄业务错误不计入
+func isGatewayFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+	return apiErr.StatusCode >= http.StatusInternalServerError
+}
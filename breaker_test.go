@@ -0,0 +1,93 @@
+package fulu_gosdk_test
+
+import (
+	"context"
+	"errors"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"github.com/t2krew/fulu-gosdk/fulutest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerIgnoresRateLimitTimeout(t *testing.T) {
+	srv := fulutest.NewServer()
+	defer srv.Close()
+	client, err := srv.NewClient(
+		fulu.WithRetryPolicy(fulu.NoRetry),
+		fulu.WithRateLimit(fulu.RateLimit{Global: fulu.Limit{Rate: 1, Burst: 1}}),
+		fulu.WithCircuitBreaker(fulu.CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetAccountInfo(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, err = client.GetAccountInfo(ctx)
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("GetAccountInfo() error = %v, want rate limit timeout", err)
+		}
+	}
+	if state := client.CircuitState(fulu.MethodGetAccountInfo); state != fulu.CircuitClosed {
+		t.Fatalf("CircuitState() = %v after local rate limit timeouts, want closed", state)
+	}
+	if _, err = client.GetAccountInfo(context.Background()); err != nil {
+		t.Fatalf("GetAccountInfo() error = %v, want request sent", err)
+	}
+	if calls := len(srv.Calls(fulu.MethodGetAccountInfo)); calls != 2 {
+		t.Fatalf("gateway calls = %d, want 2", calls)
+	}
+}
+
+func TestCircuitBreakerIgnoresRequestsStartedBeforeTrip(t *testing.T) {
+	srv := fulutest.NewServer()
+	defer srv.Close()
+	srv.ScriptFaults(fulu.MethodGetAccountInfo,
+		fulutest.Fault{Kind: fulutest.FaultLatency, Latency: 300 * time.Millisecond},
+		fulutest.Fault{Kind: fulutest.FaultHTTPStatus},
+		fulutest.Fault{Kind: fulutest.FaultLatency, Latency: 300 * time.Millisecond},
+	)
+	client, err := srv.NewClient(
+		fulu.WithRetryPolicy(fulu.NoRetry),
+		fulu.WithCircuitBreaker(fulu.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: 100 * time.Millisecond}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		ctx   = context.Background()
+		call  = func(ch chan<- error) { _, err := client.GetAccountInfo(ctx); ch <- err }
+		slow  = make(chan error, 1)
+		probe = make(chan error, 1)
+	)
+
+	go call(slow)
+	time.Sleep(50 * time.Millisecond)
+	if _, err = client.GetAccountInfo(ctx); err == nil {
+		t.Fatal("GetAccountInfo() error = nil, want 503")
+	}
+	time.Sleep(150 * time.Millisecond)
+	go call(probe)
+	time.Sleep(50 * time.Millisecond)
+
+	// 熔断前发出的慢请求在半开状态下成功，不应关闭熔断或占用探测名额
+	if err = <-slow; err != nil {
+		t.Fatalf("slow GetAccountInfo() error = %v", err)
+	}
+	if state := client.CircuitState(fulu.MethodGetAccountInfo); state != fulu.CircuitHalfOpen {
+		t.Fatalf("CircuitState() = %v after pre-trip request finished, want half-open", state)
+	}
+	if _, err = client.GetAccountInfo(ctx); !errors.Is(err, fulu.ErrCircuitOpen) {
+		t.Fatalf("GetAccountInfo() error = %v while probe in flight, want ErrCircuitOpen", err)
+	}
+
+	if err = <-probe; err != nil {
+		t.Fatalf("probe GetAccountInfo() error = %v", err)
+	}
+	if state := client.CircuitState(fulu.MethodGetAccountInfo); state != fulu.CircuitClosed {
+		t.Fatalf("CircuitState() = %v after probe succeeded, want closed", state)
+	}
+}
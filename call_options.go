@@ -0,0 +1,76 @@
+package fulu_gosdk
+
+import (
+	"context"
+	"time"
+)
+
+// CallOption 单次调用的可选配置
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	appAuthToken    string
+	hasAppAuthToken bool
+	timeout         time.Duration
+	endpoint        string
+	headers         map[string]string
+}
+
+// WithAppAuthToken 以指定授权令牌代商户调用
+func WithAppAuthToken(token string) CallOption {
+	return func(o *callOptions) {
+		o.appAuthToken = token
+		o.hasAppAuthToken = true
+	}
+}
+
+// WithTimeout 设置本次调用的超时时间，覆盖接口默认超时
+func WithTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithEndpoint 设置本次调用的网关地址
+func WithEndpoint(endpoint string) CallOption {
+	return func(o *callOptions) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithHeader 添加本次调用的http请求头
+func WithHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.headers == nil {
+			o.headers = make(map[string]string)
+		}
+		o.headers[key] = value
+	}
+}
+
+type callOptionsKey struct{}
+
+// ContextWithCallOptions 通过ctx传递单次调用配置，仅用于GetProductInfo、GetMobileInfo
+// 等无法追加CallOption参数的方法，WaitForOrder、SafeCreate及GetReconciliation使用参数中的CallOptions
+func ContextWithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	var merged []CallOption
+	if parent, ok := ctx.Value(callOptionsKey{}).([]CallOption); ok {
+		merged = append(merged, parent...)
+	}
+	merged = append(merged, opts...)
+	return context.WithValue(ctx, callOptionsKey{}, merged)
+}
+
+// newCallOptions 合并ctx及参数中的配置，参数中的配置优先
+func newCallOptions(ctx context.Context, opts []CallOption) *callOptions {
+	var options callOptions
+	if fromCtx, ok := ctx.Value(callOptionsKey{}).([]CallOption); ok {
+		for _, opt := range fromCtx {
+			opt(&options)
+		}
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &options
+}
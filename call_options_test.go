@@ -0,0 +1,174 @@
+package fulu_gosdk_test
+
+import (
+	"context"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"github.com/t2krew/fulu-gosdk/fulutest"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// wireRequest 网关收到的请求
+type wireRequest struct {
+	server string
+	header string
+}
+
+// wireGateway 两个地址共用同一个模拟网关，记录每个请求到达的地址及请求头
+type wireGateway struct {
+	*fulutest.Gateway
+	primary  *httptest.Server
+	override *httptest.Server
+
+	mu       sync.Mutex
+	requests []wireRequest
+}
+
+func newWireGateway(t *testing.T) *wireGateway {
+	t.Helper()
+	gateway, err := fulutest.NewGateway()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &wireGateway{Gateway: gateway}
+	g.primary = httptest.NewServer(g.handler("primary"))
+	g.override = httptest.NewServer(g.handler("override"))
+	t.Cleanup(func() {
+		g.primary.Close()
+		g.override.Close()
+		g.Gateway.Close()
+	})
+	return g
+}
+
+func (g *wireGateway) handler(server string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			g.mu.Lock()
+			g.requests = append(g.requests, wireRequest{server: server, header: r.Header.Get("X-Fulu-Tenant")})
+			g.mu.Unlock()
+		}
+		g.Gateway.ServeHTTP(w, r)
+	})
+}
+
+func (g *wireGateway) wireRequests() []wireRequest {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]wireRequest(nil), g.requests...)
+}
+
+func TestCallOptionsReachWire(t *testing.T) {
+	const token = "merchant-token"
+	var (
+		fastBackoff = fulu.Backoff{Initial: 10 * time.Millisecond, Multiplier: 1}
+		start       = time.Now().Add(-time.Hour)
+		end         = time.Now().Add(time.Hour)
+	)
+	tests := []struct {
+		name   string
+		method fulu.Method
+		call   func(ctx context.Context, client *fulu.Client, opts []fulu.CallOption) error
+	}{
+		{
+			name:   "QueryOrder",
+			method: fulu.MethodQueryOrder,
+			call: func(ctx context.Context, client *fulu.Client, opts []fulu.CallOption) error {
+				_, err := client.QueryOrder(ctx, "seeded-001", opts...)
+				return err
+			},
+		},
+		{
+			name:   "WaitForOrder",
+			method: fulu.MethodQueryOrder,
+			call: func(ctx context.Context, client *fulu.Client, opts []fulu.CallOption) error {
+				_, err := client.WaitForOrder(ctx, "seeded-001", &fulu.WaitOptions{Backoff: fastBackoff, MaxAttempts: 3, CallOptions: opts})
+				return err
+			},
+		},
+		{
+			name:   "SafeCreateDirectOrder",
+			method: fulu.MethodCreateDirectOrder,
+			call: func(ctx context.Context, client *fulu.Client, opts []fulu.CallOption) error {
+				_, err := client.SafeCreateDirectOrder(ctx, fulu.CreateDirectOrderBizContent{
+					ProductID:     1001,
+					CustomerOrder: "safe-001",
+					ChargeAccount: "player",
+					BuyNum:        1,
+				}, &fulu.SafeCreateOptions{Backoff: fastBackoff, CallOptions: opts})
+				return err
+			},
+		},
+		{
+			name:   "GetReconciliation",
+			method: fulu.MethodApplyReconciliation,
+			call: func(ctx context.Context, client *fulu.Client, opts []fulu.CallOption) error {
+				_, err := client.GetReconciliation(ctx, start, end, &fulu.ReconciliationOptions{PollInterval: 10 * time.Millisecond, CallOptions: opts})
+				return err
+			},
+		},
+		{
+			name:   "GetProductInfo",
+			method: fulu.MethodGetProductInfo,
+			call: func(ctx context.Context, client *fulu.Client, opts []fulu.CallOption) error {
+				_, err := client.GetProductInfo(fulu.ContextWithCallOptions(ctx, opts...), "1001")
+				return err
+			},
+		},
+		{
+			name:   "GetMobileInfo",
+			method: fulu.MethodGetMobileInfo,
+			call: func(ctx context.Context, client *fulu.Client, opts []fulu.CallOption) error {
+				_, err := client.GetMobileInfo(fulu.ContextWithCallOptions(ctx, opts...), "13800138000")
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway := newWireGateway(t)
+			gateway.SetBalance(100)
+			gateway.SeedProducts(fulu.ProductInfo{ProductID: 1001, ProductName: "游戏直充", PurchasePrice: 10})
+			gateway.SeedOrders(fulu.Order{CustomerOrderNO: "seeded-001", ProductID: 1001})
+
+			client, err := fulu.NewWithClient(gateway.Config(gateway.primary.URL), gateway.primary.Client(), fulu.WithRetryPolicy(fulu.NoRetry))
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := []fulu.CallOption{
+				fulu.WithAppAuthToken(token),
+				fulu.WithEndpoint(gateway.override.URL),
+				fulu.WithHeader("X-Fulu-Tenant", "shop-1"),
+			}
+			if err = tt.call(context.Background(), client, opts); err != nil {
+				t.Fatal(err)
+			}
+
+			requests := gateway.wireRequests()
+			if len(requests) == 0 {
+				t.Fatal("no request reached the gateway")
+			}
+			for _, req := range requests {
+				if req.server != "override" || req.header != "shop-1" {
+					t.Fatalf("request = %+v, want endpoint override and X-Fulu-Tenant shop-1", req)
+				}
+			}
+			for _, call := range gateway.Calls() {
+				if call.Params.AppAuthToken != token {
+					t.Fatalf("%s app_auth_token = %q, want %q", call.Method, call.Params.AppAuthToken, token)
+				}
+			}
+
+			// 首次请求延迟超过WithTimeout时应在超时后返回，而非等待网关响应
+			gateway.ScriptFaults(tt.method, fulutest.Fault{Kind: fulutest.FaultLatency, Latency: time.Second})
+			var begin = time.Now()
+			_ = tt.call(context.Background(), client, append(opts, fulu.WithTimeout(100*time.Millisecond)))
+			if elapsed := time.Since(begin); elapsed >= time.Second {
+				t.Fatalf("call with WithTimeout took %v, want the slow request aborted", elapsed)
+			}
+		})
+	}
+}
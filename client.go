@@ -5,13 +5,13 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"errors"
-	"fmt"
 	"github.com/go-resty/resty/v2"
 	jsoniter "github.com/json-iterator/go"
-	"log"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"log/slog"
 	"net/http"
-	"sort"
-	"strings"
+	"os"
 	"time"
 )
 
@@ -31,6 +31,8 @@ const (
 	MethodCreateMobileOrder       = Method("fulu.order.mobile.add")
 	MethodQueryOrder              = Method("fulu.order.info.get")
 	MethodQueryOrderExtend        = Method("fulu.order.extend.get")
+	MethodApplyReconciliation     = Method("fulu.order.reconciliation.apply")
+	MethodGetReconciliation       = Method("fulu.order.reconciliation.get")
 )
 
 const (
@@ -58,7 +60,10 @@ type RespData struct {
 }
 
 type Config struct {
+	// Deprecated: 使用LogLevel，Debug为true时等同于LogLevel为debug
 	Debug        bool   `json:"debug" yaml:"debug"`
+	LogLevel     string `json:"log_level" yaml:"log_level"`     // 日志级别：debug、info、warn、error，为空时不输出日志
+	VerifySign   bool   `json:"verify_sign" yaml:"verify_sign"` // 校验响应签名
 	Endpoint     string `json:"endpoint" yaml:"endpoint"`
 	AppKey       string `json:"app_key" yaml:"app_key"`
 	AppSecret    string `json:"app_secret" yaml:"app_secret"`
@@ -69,64 +74,151 @@ type Config struct {
 	AppAuthToken string `json:"app_auth_token" yaml:"app_auth_token"`
 }
 
+// Client 福禄sdk实例，构造完成后配置不可变，可并发使用
 type Client struct {
-	cfg     Config
-	debug   bool
-	appkey  string
-	httpCli *resty.Client
+	cfg      Config
+	debug    bool
+	logger   *slog.Logger
+	logLevel slog.Level
+	appkey   string
+	httpCli  *resty.Client
+	retry    RetryPolicy
+	limiter  *rateLimiter
+	breaker  *circuitBreaker
+	signer   Signer
+	redactor *Redactor
+	tracer   trace.Tracer
+	metrics  Metrics
+	finished *finishedOrders
+
+	interceptors []Interceptor
+	invoker      Invoker
 }
 
 // New 初始化福禄sdk实例
-func New(cfg Config) (*Client, error) {
-	return newclient(cfg, resty.New())
+func New(cfg Config, opts ...Option) (*Client, error) {
+	return newclient(cfg, resty.New(), opts...)
 }
 
 // NewWithClient 初始化自定义http.Client的福禄sdk实例
-func NewWithClient(cfg Config, httpClient *http.Client) (*Client, error) {
-	return newclient(cfg, resty.NewWithClient(httpClient))
+func NewWithClient(cfg Config, httpClient *http.Client, opts ...Option) (*Client, error) {
+	return newclient(cfg, resty.NewWithClient(httpClient), opts...)
 }
 
-// Request 发起接口请求
-func (c *Client) Request(ctx context.Context, method Method, bizContent interface{}, result interface{}) error {
+// Request 发起接口请求，请求依次经过拦截器链，只读或幂等接口失败时按重试策略重试
+func (c *Client) Request(ctx context.Context, method Method, bizContent interface{}, result interface{}, opts ...CallOption) error {
 	rawContent, err := jsoniter.MarshalToString(bizContent)
 	if err != nil {
 		return err
 	}
-	var params = c.newParams(method, rawContent)
 
+	var (
+		options = newCallOptions(ctx, opts)
+		timeout = method.Info().Timeout
+	)
+	if options.timeout > 0 {
+		timeout = options.timeout
+	}
+	if _, ok := ctx.Deadline(); timeout > 0 && (!ok || options.timeout > 0) {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var call = &Call{
+		Method:        method,
+		BizContent:    bizContent,
+		RawBizContent: rawContent,
+		Params:        c.newParams(method, rawContent),
+		Result:        result,
+		Attempt:       1,
+		Endpoint:      c.cfg.Endpoint,
+		Headers:       options.headers,
+	}
+	if options.hasAppAuthToken {
+		call.Params.AppAuthToken = options.appAuthToken
+	}
+	if options.endpoint != "" {
+		call.Endpoint = options.endpoint
+	}
+
+	ctx, span := c.startCallSpan(ctx, call)
+	err = c.invoker(ctx, call)
+	endSpan(span, call, err)
+	return err
+}
+
+// invoke 签名并发送请求，拦截器链的最内层
+func (c *Client) invoke(ctx context.Context, call *Call) (err error) {
+	var (
+		method = call.Method
+		params = call.Params
+	)
+	call.Response = nil
+	call.StatusCode = 0
+	params.Timestamp = time.Now().Format(TimestampFormat)
+
+	ctx, span := c.tracer.Start(ctx, "fulu.attempt", trace.WithAttributes(AttrAttempt.Int(call.Attempt)))
+	defer func() {
+		endSpan(span, call, err)
+	}()
+
+	_, signSpan := c.tracer.Start(ctx, "fulu.sign")
 	sign, signStr, err := c.getSign(params)
+	signSpan.End()
 	if err != nil {
 		return err
 	}
 	if c.debug {
-		log.Printf("[fulu-sdk] [%s] sign_str: %s, sign: %s", method, signStr, sign)
+		c.log(ctx, slog.LevelDebug, "fulu sign",
+			slog.String("method", string(method)),
+			slog.String("sign_str", c.redactor.Redact(signStr)),
+			slog.String("sign", sign),
+		)
 	}
 
 	params.Sign = sign
 
-	resp, err := c.httpCli.SetDebug(c.debug).R().SetContext(ctx).SetBody(params).Post(c.cfg.Endpoint)
+	resp, err := c.httpCli.R().SetContext(ctx).SetHeaders(call.Headers).SetBody(params).Post(call.Endpoint)
 	if err != nil {
 		return err
 	}
+	call.StatusCode = resp.StatusCode()
 	if !resp.IsSuccess() {
-		return fmt.Errorf("api method [%s] call failed, status code is %d, %s", method, resp.StatusCode(), resp.Status())
+		return &APIError{Method: method, StatusCode: resp.StatusCode(), Body: resp.Body()}
 	}
 
 	var respdata RespData
 	err = jsoniter.Unmarshal(resp.Body(), &respdata)
 	if err != nil {
-		return err
+		return &APIError{Method: method, StatusCode: resp.StatusCode(), Body: resp.Body(), Err: err}
 	}
 
+	if c.cfg.VerifySign {
+		err = VerifySign(c.signer, resp.Body())
+		if err != nil {
+			// 签名不符时响应内容不可信，不设置Code、Message及call.Response，
+			// 避免errors.Is、日志及链路追踪使用伪造的错误码
+			return &APIError{Method: method, StatusCode: resp.StatusCode(), Body: resp.Body(), Err: err}
+		}
+	}
+	call.Response = &respdata
+
 	switch respdata.Code {
-	case 0:
-		err := jsoniter.Unmarshal([]byte(respdata.Result), &result)
+	case CodeSuccess:
+		err := jsoniter.Unmarshal([]byte(respdata.Result), &call.Result)
 		if err != nil {
-			return err
+			return &APIError{Method: method, StatusCode: resp.StatusCode(), Body: resp.Body(), Err: err}
 		}
 		return nil
 	default:
-		return fmt.Errorf("errno: %d, errmsg:%s", respdata.Code, respdata.Message)
+		return &APIError{
+			Method:     method,
+			Code:       respdata.Code,
+			Message:    respdata.Message,
+			StatusCode: resp.StatusCode(),
+			Body:       resp.Body(),
+		}
 	}
 }
 
@@ -139,7 +231,7 @@ var defaultConfig = Config{
 	AppAuthToken: "",
 }
 
-func newclient(config Config, cli *resty.Client) (*Client, error) {
+func newclient(config Config, cli *resty.Client, opts ...Option) (*Client, error) {
 	var cfg = defaultConfig
 	if config.AppKey != "" {
 		cfg.AppKey = config.AppKey
@@ -160,6 +252,12 @@ func newclient(config Config, cli *resty.Client) (*Client, error) {
 	}
 
 	cfg.Debug = config.Debug
+	cfg.LogLevel = config.LogLevel
+	logLevel, logEnabled, err := parseLogLevel(cfg.LogLevel, cfg.Debug)
+	if err != nil {
+		return nil, err
+	}
+	cfg.VerifySign = config.VerifySign
 
 	if config.Format != "" {
 		cfg.Format = config.Format
@@ -177,16 +275,64 @@ func newclient(config Config, cli *resty.Client) (*Client, error) {
 		cfg.AppAuthToken = config.AppAuthToken
 	}
 
-	return &Client{
-		cfg:     cfg,
-		debug:   cfg.Debug,
-		appkey:  cfg.AppKey,
-		httpCli: cli,
-	}, nil
+	var client = &Client{
+		cfg:      cfg,
+		logLevel: logLevel,
+		appkey:   cfg.AppKey,
+		httpCli:  cli,
+		retry:    DefaultRetryPolicy.clone(),
+		redactor: NewRedactor(DefaultRedactRules()...),
+		tracer:   noop.NewTracerProvider().Tracer(tracerName),
+		metrics:  nopMetrics{},
+		finished: newFinishedOrders(finishedOrderCacheSize),
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	client.redactor = client.redactor.WithSecrets(cfg.AppSecret, cfg.AppAuthToken)
+	if client.logger == nil && logEnabled {
+		client.logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel}))
+	}
+	client.debug = client.logger != nil && client.logLevel <= slog.LevelDebug
+	// resty.Client的配置并非并发安全，只在构造时设置
+	client.httpCli.SetLogger(&restyLogger{client: client}).SetDebug(client.debug)
+	if client.signer == nil {
+		signer, err := NewSigner(cfg.SignType, cfg.AppSecret)
+		if err != nil {
+			return nil, err
+		}
+		client.signer = signer
+	}
+
+	var interceptors = append([]Interceptor{}, client.interceptors...)
+	interceptors = append(interceptors, MetricsInterceptor(func(method Method, elapsed time.Duration, err error) {
+		client.metrics.ObserveCall(method, ErrorCode(err), elapsed)
+	}))
+	interceptors = append(interceptors, RetryInterceptor(client.retry))
+	if client.logger != nil {
+		interceptors = append(interceptors, client.logInterceptor)
+	}
+	// 限流在熔断之外，本地等待额度超时不计入熔断统计
+	if client.limiter != nil {
+		interceptors = append(interceptors, client.limiter.interceptor)
+	}
+	if client.breaker != nil {
+		interceptors = append(interceptors, client.breaker.interceptor)
+	}
+	client.invoker = chainInterceptors(interceptors, client.invoke)
+	return client, nil
 }
 
 func (c *Client) getSign(params *ReqParams) (sign string, signStr string, err error) {
-	return getSignWithSecret(params, c.cfg.AppSecret)
+	signStr, err = SignPayload(params)
+	if err != nil {
+		return "", "", err
+	}
+	sign, err = c.signer.Sign(signStr)
+	if err != nil {
+		return "", "", err
+	}
+	return sign, signStr, nil
 }
 
 func (c *Client) newParams(method Method, bizContent string) *ReqParams {
@@ -203,38 +349,6 @@ func (c *Client) newParams(method Method, bizContent string) *ReqParams {
 	}
 }
 
-func getSignWithSecret(params *ReqParams, secret string) (sign string, signStr string, err error) {
-	var signdata map[string]string
-
-	raw, err := jsoniter.Marshal(params)
-	if err != nil {
-		return "", "", err
-	}
-
-	err = jsoniter.Unmarshal(raw, &signdata)
-	if err != nil {
-		return "", "", err
-	}
-	delete(signdata, "sign")
-
-	serializeSigndata, err := jsoniter.MarshalToString(signdata)
-	if err != nil {
-		return "", "", err
-	}
-	var (
-		runeArray = []rune(serializeSigndata)
-		charArray = make([]string, 0, len(runeArray))
-	)
-	for _, char := range runeArray {
-		charArray = append(charArray, string(char))
-	}
-	sort.Strings(charArray)
-
-	signStr = strings.Join(charArray, "") + secret
-
-	return strings.ToLower(MD5(signStr)), signStr, nil
-}
-
 func MD5(str string) string {
 	hasher := md5.New()
 	hasher.Write([]byte(str))
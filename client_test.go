@@ -0,0 +1,131 @@
+package fulu_gosdk_test
+
+import (
+	"context"
+	"errors"
+	jsoniter "github.com/json-iterator/go"
+	fulu "github.com/t2krew/fulu-gosdk"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const (
+	testAppKey    = "test-app-key"
+	testAppSecret = "test-app-secret"
+)
+
+// signResponse 按福禄规则为响应签名
+func signResponse(t *testing.T, resp fulu.RespData) fulu.RespData {
+	t.Helper()
+	signer, err := fulu.NewSigner("md5", testAppSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Sign = ""
+	payload, err := fulu.SignPayload(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Sign, err = signer.Sign(payload); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestClientVerifySign(t *testing.T) {
+	var order = `{"order_id":"F001","customer_order_no":"C001","order_state":"success"}`
+	tests := []struct {
+		name     string
+		response func(t *testing.T) fulu.RespData
+		wantErr  bool
+	}{
+		{
+			name: "valid",
+			response: func(t *testing.T) fulu.RespData {
+				return signResponse(t, fulu.RespData{Code: fulu.CodeSuccess, Message: "success", Result: order})
+			},
+		},
+		{
+			name: "missing",
+			response: func(t *testing.T) fulu.RespData {
+				return fulu.RespData{Code: fulu.CodeSuccess, Message: "success", Result: order}
+			},
+			wantErr: true,
+		},
+		{
+			name: "tampered",
+			response: func(t *testing.T) fulu.RespData {
+				resp := signResponse(t, fulu.RespData{Code: fulu.CodeSuccess, Message: "success", Result: order})
+				resp.Code = fulu.CodeOrderNotFound
+				resp.Message = "订单不存在"
+				resp.Result = ""
+				return resp
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := tt.response(t)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_ = jsoniter.NewEncoder(w).Encode(resp)
+			}))
+			defer srv.Close()
+
+			var (
+				response *fulu.RespData
+				exporter = tracetest.NewInMemoryExporter()
+				provider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			)
+			defer provider.Shutdown(context.Background())
+			client, err := fulu.New(fulu.Config{
+				Endpoint:   srv.URL,
+				AppKey:     testAppKey,
+				AppSecret:  testAppSecret,
+				VerifySign: true,
+			},
+				fulu.WithRetryPolicy(fulu.NoRetry),
+				fulu.WithTracerProvider(provider),
+				fulu.WithInterceptors(func(ctx context.Context, call *fulu.Call, next fulu.Invoker) error {
+					err := next(ctx, call)
+					response = call.Response
+					return err
+				}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := client.QueryOrder(context.Background(), "C001")
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("QueryOrder() error = %v", err)
+				}
+				if result.OrderID != "F001" {
+					t.Fatalf("QueryOrder() order_id = %q, want F001", result.OrderID)
+				}
+				return
+			}
+			if !errors.Is(err, fulu.ErrSignMismatch) {
+				t.Fatalf("QueryOrder() error = %v, want ErrSignMismatch", err)
+			}
+			if errors.Is(err, fulu.ErrOrderNotFound) {
+				t.Fatalf("QueryOrder() error matches ErrOrderNotFound from unverified response")
+			}
+			if code := fulu.ErrorCode(err); code == fulu.CodeOrderNotFound {
+				t.Fatalf("ErrorCode() = %d, want code not taken from unverified response", code)
+			}
+			if response != nil {
+				t.Fatalf("interceptor saw call.Response = %+v from unverified response", response)
+			}
+			for _, span := range exporter.GetSpans() {
+				if _, ok := spanAttr(span, fulu.AttrCode); ok {
+					t.Fatalf("span %s has fulu.code from unverified response", span.Name)
+				}
+			}
+		})
+	}
+}
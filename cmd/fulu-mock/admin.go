@@ -0,0 +1,174 @@
+package main
+
+import (
+	"errors"
+	jsoniter "github.com/json-iterator/go"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"github.com/t2krew/fulu-gosdk/fulutest"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	adminPrefix      = "/admin/"
+	maxAdminBodySize = 1 << 20
+)
+
+// adminHandler 运行时修改模拟网关状态的管理接口
+//
+//	GET    /admin/state                       商品、订单及余额
+//	POST   /admin/seed                        按初始数据格式批量写入，凭证及签名类型不生效
+//	PUT    /admin/balance                     {"balance": 100}
+//	PUT    /admin/account                     {"disabled": true}
+//	POST   /admin/products                    [商品]
+//	PUT    /admin/products/{id}/status        {"sales_status": "下架", "stock_status": "断货"}
+//	POST   /admin/orders/{no}/finish          {"state": "failed"}
+//	PUT    /admin/lifecycles/products/{id}    生命周期
+//	PUT    /admin/lifecycles/orders/{no}      生命周期
+//	POST   /admin/faults/rules                [故障规则]
+//	POST   /admin/faults/scripts/{method}     [故障]
+//	DELETE /admin/faults                      清除故障配置
+//	GET    /admin/calls?method=               收到的请求，DELETE清空
+//	GET    /admin/notifications               已发出的订单结果推送
+type adminHandler struct {
+	gateway *fulutest.Gateway
+}
+
+type adminState struct {
+	Balance  float64            `json:"balance"`
+	Products []fulu.ProductInfo `json:"products"`
+	Orders   []fulu.Order       `json:"orders"`
+}
+
+func (h *adminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		path = strings.Trim(strings.TrimPrefix(r.URL.Path, adminPrefix), "/")
+		segs = strings.Split(path, "/")
+		err  error
+	)
+	switch {
+	case path == "state" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, adminState{
+			Balance:  h.gateway.Balance(),
+			Products: h.gateway.Products(),
+			Orders:   h.gateway.Orders(),
+		})
+		return
+	case path == "seed" && r.Method == http.MethodPost:
+		var seed Seed
+		if err = readJSON(r, &seed); err == nil {
+			err = seed.Apply(h.gateway)
+		}
+	case path == "balance" && r.Method == http.MethodPut:
+		var body struct {
+			Balance float64 `json:"balance"`
+		}
+		if err = readJSON(r, &body); err == nil {
+			h.gateway.SetBalance(body.Balance)
+		}
+	case path == "account" && r.Method == http.MethodPut:
+		var body struct {
+			Disabled bool `json:"disabled"`
+		}
+		if err = readJSON(r, &body); err == nil {
+			h.gateway.SetAccountDisabled(body.Disabled)
+		}
+	case path == "products" && r.Method == http.MethodPost:
+		var products []Product
+		if err = readJSON(r, &products); err == nil {
+			for _, product := range products {
+				h.gateway.SeedProducts(product.info())
+			}
+		}
+	case len(segs) == 3 && segs[0] == "products" && segs[2] == "status" && r.Method == http.MethodPut:
+		var body struct {
+			SalesStatus string `json:"sales_status"`
+			StockStatus string `json:"stock_status"`
+		}
+		productID, perr := strconv.ParseInt(segs[1], 10, 64)
+		if err = errors.Join(perr, readJSON(r, &body)); err == nil &&
+			!h.gateway.SetProductStatus(productID, fulu.SaleStatus(body.SalesStatus), fulu.StockStatus(body.StockStatus)) {
+			writeError(w, http.StatusNotFound, "product not found")
+			return
+		}
+	case len(segs) == 3 && segs[0] == "orders" && segs[2] == "finish" && r.Method == http.MethodPost:
+		var body struct {
+			State fulu.OrderState `json:"state"`
+		}
+		if err = readJSON(r, &body); err == nil {
+			err = h.gateway.FinishOrder(segs[1], body.State)
+		}
+	case len(segs) == 3 && segs[0] == "lifecycles" && r.Method == http.MethodPut:
+		var lifecycle Lifecycle
+		if err = readJSON(r, &lifecycle); err != nil {
+			break
+		}
+		switch segs[1] {
+		case "products":
+			var productID int64
+			if productID, err = strconv.ParseInt(segs[2], 10, 64); err == nil {
+				h.gateway.SetProductLifecycle(productID, lifecycle.lifecycle())
+			}
+		case "orders":
+			h.gateway.SetOrderLifecycle(segs[2], lifecycle.lifecycle())
+		default:
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+	case path == "faults/rules" && r.Method == http.MethodPost:
+		var rules []FaultRule
+		if err = readJSON(r, &rules); err == nil {
+			for _, rule := range rules {
+				h.gateway.AddFaultRule(fulutest.FaultRule{Method: rule.Method, Probability: rule.Probability, Fault: rule.Fault.fault()})
+			}
+		}
+	case len(segs) == 3 && segs[0] == "faults" && segs[1] == "scripts" && r.Method == http.MethodPost:
+		var faults []Fault
+		if err = readJSON(r, &faults); err == nil {
+			h.gateway.ScriptFaults(fulu.Method(segs[2]), toFaults(faults)...)
+		}
+	case path == "faults" && r.Method == http.MethodDelete:
+		h.gateway.ClearFaults()
+	case path == "calls" && r.Method == http.MethodGet:
+		var methods []fulu.Method
+		for _, method := range r.URL.Query()["method"] {
+			methods = append(methods, fulu.Method(method))
+		}
+		writeJSON(w, http.StatusOK, h.gateway.Calls(methods...))
+		return
+	case path == "calls" && r.Method == http.MethodDelete:
+		h.gateway.ResetCalls()
+	case path == "notifications" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, h.gateway.Notifications())
+		return
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func readJSON(r *http.Request, v interface{}) error {
+	raw, err := io.ReadAll(io.LimitReader(r.Body, maxAdminBodySize))
+	if err != nil {
+		return err
+	}
+	return jsoniter.Unmarshal(raw, v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = jsoniter.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,133 @@
+// fulu-mock 以独立进程运行福禄模拟网关，供非Go项目联调使用
+//
+//	fulu-mock -addr :8080 -seed seed.yaml
+//
+// 客户端Endpoint配置为 http://host:port/ ，管理接口见 /admin/ 。
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"github.com/t2krew/fulu-gosdk/fulutest"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	var (
+		addr            = flag.String("addr", ":8080", "listen address")
+		seedFile        = flag.String("seed", "", "seed file (.yaml, .yml or .json)")
+		appKey          = flag.String("app-key", "", "app_key, overrides seed file (default "+fulutest.DefaultAppKey+")")
+		appSecret       = flag.String("app-secret", "", "app_secret, overrides seed file (default "+fulutest.DefaultAppSecret+")")
+		signType        = flag.String("sign-type", "", "sign type, overrides seed file (default md5)")
+		notifyURL       = flag.String("notify-url", "", "default order notification url, overrides seed file")
+		timestampWindow = flag.Duration("timestamp-window", 10*time.Minute, "allowed request timestamp skew, negative to disable")
+		faultSeed       = flag.Int64("fault-seed", 0, "random seed for probabilistic faults, 0 for time based")
+	)
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	if err := run(logger, *addr, *seedFile, *appKey, *appSecret, *signType, *notifyURL, *timestampWindow, *faultSeed); err != nil {
+		logger.Error("fulu-mock exited", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+}
+
+func run(logger *slog.Logger, addr, seedFile, appKey, appSecret, signType, notifyURL string, timestampWindow time.Duration, faultSeed int64) error {
+	var seed = &Seed{}
+	if seedFile != "" {
+		var err error
+		seed, err = LoadSeed(seedFile)
+		if err != nil {
+			return err
+		}
+	}
+	if appKey != "" {
+		seed.AppKey = appKey
+	}
+	if appSecret != "" {
+		seed.AppSecret = appSecret
+	}
+	if signType != "" {
+		seed.SignType = signType
+	}
+	if notifyURL != "" {
+		seed.NotifyURL = notifyURL
+	}
+
+	var opts = []fulutest.Option{
+		fulutest.WithTimestampWindow(timestampWindow),
+		fulutest.WithCallHook(logCall(logger)),
+	}
+	if seed.AppKey != "" || seed.AppSecret != "" {
+		opts = append(opts, fulutest.WithCredentials(seed.AppKey, seed.AppSecret))
+	}
+	if seed.SignType != "" {
+		opts = append(opts, fulutest.WithSignType(seed.SignType))
+	}
+	if faultSeed != 0 {
+		opts = append(opts, fulutest.WithFaultSeed(faultSeed))
+	}
+	gateway, err := fulutest.NewGateway(opts...)
+	if err != nil {
+		return err
+	}
+	defer gateway.Close()
+	if err = seed.Apply(gateway); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(adminPrefix, &adminHandler{gateway: gateway})
+	mux.Handle("/", gateway)
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	cfg := gateway.Config("")
+	logger.Info("fulu-mock listening",
+		slog.String("addr", addr),
+		slog.String("app_key", cfg.AppKey),
+		slog.String("sign_type", cfg.SignType),
+		slog.Int("products", len(gateway.Products())),
+	)
+	if err = srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// logCall 记录网关收到的请求，biz_content按客户端默认规则脱敏
+func logCall(logger *slog.Logger) func(call fulutest.Call) {
+	redactor := fulu.NewRedactor(fulu.DefaultRedactRules()...)
+	return func(call fulutest.Call) {
+		var level = slog.LevelInfo
+		if call.Code != fulu.CodeSuccess {
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("method", string(call.Method)),
+			slog.Int("code", call.Code),
+			slog.String("message", call.Message),
+			slog.Duration("latency", time.Since(call.Time)),
+			slog.String("biz_content", redactor.Redact(call.BizContent)),
+		}
+		if call.Fault != fulutest.FaultNone {
+			attrs = append(attrs, slog.String("fault", string(call.Fault)))
+		}
+		logger.LogAttrs(context.Background(), level, "fulu call", attrs...)
+	}
+}
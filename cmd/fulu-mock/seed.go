@@ -0,0 +1,265 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	jsoniter "github.com/json-iterator/go"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"github.com/t2krew/fulu-gosdk/fulutest"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Seed 模拟网关初始数据，支持yaml及json格式
+type Seed struct {
+	AppKey          string         `json:"app_key" yaml:"app_key"`
+	AppSecret       string         `json:"app_secret" yaml:"app_secret"`
+	SignType        string         `json:"sign_type" yaml:"sign_type"`
+	NotifyURL       string         `json:"notify_url" yaml:"notify_url"`
+	Balance         *float64       `json:"balance" yaml:"balance"`
+	AccountDisabled *bool          `json:"account_disabled" yaml:"account_disabled"`
+	Products        []Product      `json:"products" yaml:"products"`
+	Orders          []Order        `json:"orders" yaml:"orders"`
+	Lifecycles      SeedLifecycles `json:"lifecycles" yaml:"lifecycles"`
+	Faults          SeedFaults     `json:"faults" yaml:"faults"`
+}
+
+// SeedLifecycles 订单生命周期，按商品编号或外部订单号配置
+type SeedLifecycles struct {
+	Products map[int64]Lifecycle  `json:"products" yaml:"products"`
+	Orders   map[string]Lifecycle `json:"orders" yaml:"orders"`
+}
+
+// SeedFaults 故障注入配置
+type SeedFaults struct {
+	Rules   []FaultRule             `json:"rules" yaml:"rules"`
+	Scripts map[fulu.Method][]Fault `json:"scripts" yaml:"scripts"`
+}
+
+// Product 商品
+type Product struct {
+	ProductID     int64   `json:"product_id" yaml:"product_id"`
+	ProductName   string  `json:"product_name" yaml:"product_name"`
+	ProductType   string  `json:"product_type" yaml:"product_type"`
+	FaceValue     float64 `json:"face_value" yaml:"face_value"`
+	PurchasePrice float64 `json:"purchase_price" yaml:"purchase_price"`
+	TemplateID    string  `json:"template_id" yaml:"template_id"`
+	SalesStatus   string  `json:"sales_status" yaml:"sales_status"`
+	StockStatus   string  `json:"stock_status" yaml:"stock_status"`
+	Details       string  `json:"details" yaml:"details"`
+}
+
+func (p Product) info() fulu.ProductInfo {
+	return fulu.ProductInfo{
+		ProductID:     p.ProductID,
+		ProductName:   p.ProductName,
+		ProductType:   p.ProductType,
+		FaceValue:     p.FaceValue,
+		PurchasePrice: p.PurchasePrice,
+		TemplateID:    p.TemplateID,
+		SalesStatus:   p.SalesStatus,
+		StockStatus:   p.StockStatus,
+		Details:       p.Details,
+	}
+}
+
+// Order 历史订单
+type Order struct {
+	OrderID              string  `json:"order_id" yaml:"order_id"`
+	CustomerOrderNO      string  `json:"customer_order_no" yaml:"customer_order_no"`
+	ProductID            int64   `json:"product_id" yaml:"product_id"`
+	ProductName          string  `json:"product_name" yaml:"product_name"`
+	ChargeAccount        string  `json:"charge_account" yaml:"charge_account"`
+	BuyNum               int     `json:"buy_num" yaml:"buy_num"`
+	OrderPrice           float64 `json:"order_price" yaml:"order_price"`
+	OrderState           string  `json:"order_state" yaml:"order_state"`
+	CreateTime           string  `json:"create_time" yaml:"create_time"`
+	FinishTime           string  `json:"finish_time" yaml:"finish_time"`
+	OperatorSerialNumber string  `json:"operator_serial_number" yaml:"operator_serial_number"`
+}
+
+func (o Order) order() fulu.Order {
+	return fulu.Order{
+		OrderID:              o.OrderID,
+		CustomerOrderNO:      o.CustomerOrderNO,
+		ProductID:            o.ProductID,
+		ProductName:          o.ProductName,
+		ChargeAccount:        o.ChargeAccount,
+		BuyNum:               o.BuyNum,
+		OrderPrice:           o.OrderPrice,
+		OrderState:           o.OrderState,
+		CreateTime:           o.CreateTime,
+		FinishTime:           o.FinishTime,
+		OperatorSerialNumber: o.OperatorSerialNumber,
+	}
+}
+
+// Card 卡密
+type Card struct {
+	CardType     int    `json:"card_type" yaml:"card_type"`
+	CardNumber   string `json:"card_number" yaml:"card_number"`
+	CardPwd      string `json:"card_pwd" yaml:"card_pwd"`
+	CardDeadline string `json:"card_deadline" yaml:"card_deadline"`
+}
+
+// Lifecycle 订单生命周期，delay为"3s"格式
+type Lifecycle struct {
+	State                string   `json:"state" yaml:"state"`
+	Delay                Duration `json:"delay" yaml:"delay"`
+	Polls                int      `json:"polls" yaml:"polls"`
+	Cards                []Card   `json:"cards" yaml:"cards"`
+	OperatorSerialNumber string   `json:"operator_serial_number" yaml:"operator_serial_number"`
+	RechargeDescription  string   `json:"recharge_description" yaml:"recharge_description"`
+	NotifyURL            string   `json:"notify_url" yaml:"notify_url"`
+}
+
+func (l Lifecycle) lifecycle() fulutest.Lifecycle {
+	var lifecycle = fulutest.Lifecycle{
+		State:                fulu.OrderState(l.State),
+		Delay:                time.Duration(l.Delay),
+		Polls:                l.Polls,
+		OperatorSerialNumber: l.OperatorSerialNumber,
+		RechargeDescription:  l.RechargeDescription,
+		NotifyURL:            l.NotifyURL,
+	}
+	for _, card := range l.Cards {
+		lifecycle.Cards = append(lifecycle.Cards, fulu.CardItem(card))
+	}
+	return lifecycle
+}
+
+// Fault 注入的故障，latency为"2s"格式
+type Fault struct {
+	Kind       string   `json:"kind" yaml:"kind"`
+	Latency    Duration `json:"latency" yaml:"latency"`
+	StatusCode int      `json:"status_code" yaml:"status_code"`
+	Code       int      `json:"code" yaml:"code"`
+	Processed  bool     `json:"processed" yaml:"processed"`
+}
+
+func (f Fault) fault() fulutest.Fault {
+	return fulutest.Fault{
+		Kind:       fulutest.FaultKind(f.Kind),
+		Latency:    time.Duration(f.Latency),
+		StatusCode: f.StatusCode,
+		Code:       f.Code,
+		Processed:  f.Processed,
+	}
+}
+
+// FaultRule 按概率注入故障
+type FaultRule struct {
+	Method      fulu.Method `json:"method" yaml:"method"`
+	Probability float64     `json:"probability" yaml:"probability"`
+	Fault       Fault       `json:"fault" yaml:"fault"`
+}
+
+// Duration 支持"1.5s"格式及纳秒数的时长
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := jsoniter.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return d.set(v)
+}
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	var v interface{}
+	if err := node.Decode(&v); err != nil {
+		return err
+	}
+	return d.set(v)
+}
+
+func (d *Duration) set(v interface{}) error {
+	switch value := v.(type) {
+	case nil:
+		*d = 0
+	case string:
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*d = Duration(duration)
+	case int:
+		*d = Duration(value)
+	case float64:
+		*d = Duration(value)
+	default:
+		return fmt.Errorf("invalid duration %v", v)
+	}
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return jsoniter.Marshal(time.Duration(d).String())
+}
+
+// LoadSeed 读取初始数据文件，按扩展名选择json或yaml
+func LoadSeed(path string) (*Seed, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var seed Seed
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = jsoniter.Unmarshal(raw, &seed)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(raw, &seed)
+	default:
+		return nil, errors.New("seed file must be .json, .yaml or .yml")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse seed file %s: %w", path, err)
+	}
+	return &seed, nil
+}
+
+// Apply 将初始数据写入网关，凭证及签名类型需在创建网关时设置
+func (s *Seed) Apply(g *fulutest.Gateway) error {
+	if s.NotifyURL != "" {
+		g.SetNotifyURL(s.NotifyURL)
+	}
+	if s.Balance != nil {
+		g.SetBalance(*s.Balance)
+	}
+	if s.AccountDisabled != nil {
+		g.SetAccountDisabled(*s.AccountDisabled)
+	}
+	for _, product := range s.Products {
+		g.SeedProducts(product.info())
+	}
+	for _, order := range s.Orders {
+		if order.CustomerOrderNO == "" {
+			return errors.New("seed order customer_order_no is empty")
+		}
+		g.SeedOrders(order.order())
+	}
+	for productID, lifecycle := range s.Lifecycles.Products {
+		g.SetProductLifecycle(productID, lifecycle.lifecycle())
+	}
+	for customerOrderNO, lifecycle := range s.Lifecycles.Orders {
+		g.SetOrderLifecycle(customerOrderNO, lifecycle.lifecycle())
+	}
+	for _, rule := range s.Faults.Rules {
+		g.AddFaultRule(fulutest.FaultRule{Method: rule.Method, Probability: rule.Probability, Fault: rule.Fault.fault()})
+	}
+	for method, faults := range s.Faults.Scripts {
+		g.ScriptFaults(method, toFaults(faults)...)
+	}
+	return nil
+}
+
+func toFaults(faults []Fault) []fulutest.Fault {
+	var result = make([]fulutest.Fault, 0, len(faults))
+	for _, fault := range faults {
+		result = append(result, fault.fault())
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package fulu_gosdk_test
+
+import (
+	"context"
+	"fmt"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"github.com/t2krew/fulu-gosdk/fulutest"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestClientConcurrentUse 多个goroutine共享同一客户端调用全部接口，需配合-race运行
+func TestClientConcurrentUse(t *testing.T) {
+	const (
+		workers    = 16
+		iterations = 5
+	)
+	srv := fulutest.NewServer()
+	defer srv.Close()
+	srv.SetBalance(1e6)
+	srv.SeedProducts(
+		fulu.ProductInfo{ProductID: 1001, ProductName: "游戏直充", PurchasePrice: 1, TemplateID: "tpl-1"},
+		fulu.ProductInfo{ProductID: 1002, ProductName: "卡密", PurchasePrice: 1},
+		fulu.ProductInfo{ProductID: 1003, ProductName: "话费50元", ProductType: fulutest.ProductTypeMobile, FaceValue: 50, PurchasePrice: 49.5},
+	)
+	srv.SeedTemplates(fulu.ProductTemplate{AddressID: "tpl-1"})
+
+	client, err := srv.NewClient(
+		fulu.WithLogger(slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		fulu.WithRateLimit(fulu.RateLimit{Global: fulu.Limit{Rate: 1e6, Burst: 1e6}}),
+		fulu.WithCircuitBreaker(fulu.DefaultCircuitBreakerConfig),
+		fulu.WithMetrics(fulu.NewExpvarMetrics("fulu_concurrency_test")),
+		fulu.WithInterceptors(fulu.MetricsInterceptor(func(fulu.Method, time.Duration, error) {})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		ctx  = context.Background()
+		wg   sync.WaitGroup
+		errs = make(chan error, workers*iterations*16)
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				var (
+					no    = fmt.Sprintf("%d-%d", w, i)
+					check = func(name string, err error) {
+						if err != nil {
+							errs <- fmt.Errorf("%s %s: %w", name, no, err)
+						}
+					}
+					err error
+				)
+				_, err = client.GetProductList(ctx, &fulu.GetProductListParams{})
+				check("GetProductList", err)
+				_, err = client.GetProductInfo(ctx, "1001")
+				check("GetProductInfo", err)
+				_, err = client.GetProductTemplate(ctx, "tpl-1")
+				check("GetProductTemplate", err)
+				_, err = client.CheckProductStock(ctx, "1002", 1)
+				check("CheckProductStock", err)
+				_, err = client.GetAccountInfo(ctx)
+				check("GetAccountInfo", err)
+				_, err = client.GetQQNickname(ctx, "10001")
+				check("GetQQNickname", err)
+				_, err = client.GetMobileInfo(ctx, "13800138000", 50)
+				check("GetMobileInfo", err)
+				_, err = client.GetMobileMaintainStatus(ctx, "13800138000", 50)
+				check("GetMobileMaintainStatus", err)
+				_, err = client.CreateDirectOrder(ctx, fulu.CreateDirectOrderBizContent{ProductID: 1001, CustomerOrder: "direct-" + no, ChargeAccount: "player", BuyNum: 1})
+				check("CreateDirectOrder", err)
+				_, err = client.CreateCardOrder(ctx, fulu.CreateCardOrderBizContent{ProductID: 1002, CustomerOrderNO: "card-" + no, BuyNum: 1})
+				check("CreateCardOrder", err)
+				_, err = client.CreateMobileOrder(ctx, fulu.CreateMobileOrderBizContent{ChargePhone: "13800138000", ChargeValue: 50, CustomerOrderNO: "mobile-" + no})
+				check("CreateMobileOrder", err)
+				_, err = client.QueryOrder(ctx, "card-"+no)
+				check("QueryOrder", err)
+				_, err = client.QueryOrderExtend(ctx, "direct-"+no)
+				check("QueryOrderExtend", err)
+				task, err := client.ApplyReconciliation(ctx, time.Now().Add(-time.Hour), time.Now())
+				check("ApplyReconciliation", err)
+				if err == nil {
+					_, err = client.QueryReconciliation(ctx, task.TaskID)
+					check("QueryReconciliation", err)
+					_, err = client.DownloadReconciliation(ctx, task.DownloadURL)
+					check("DownloadReconciliation", err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if got, want := len(srv.Orders()), workers*iterations*3; got != want {
+		t.Fatalf("gateway orders = %d, want %d", got, want)
+	}
+}
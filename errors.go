@@ -0,0 +1,176 @@
+package fulu_gosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// 福禄网关及业务错误码
+const (
+	CodeSuccess              = 0    // 成功
+	CodeMissingMethod        = 1000 // 必须传入API接口名称
+	CodeInvalidMethod        = 1001 // 无效的API接口名称
+	CodeMissingTimestamp     = 1002 // 必须传入时间戳
+	CodeInvalidTimestamp     = 1003 // 时间戳格式错误
+	CodeTimestampExpired     = 1004 // 时间戳已超过有效期
+	CodeMissingAppKey        = 1005 // 必须传入app_key
+	CodeInvalidAppKey        = 1006 // 无效的app_key
+	CodeMissingVersion       = 1007 // 必须传入版本号
+	CodeInvalidVersion       = 1008 // 无效的版本号
+	CodeMissingSign          = 1009 // 必须传入签名
+	CodeSignError            = 1010 // 无效签名
+	CodeMissingBizContent    = 1011 // 必须传入业务参数
+	CodeInvalidBizContent    = 1012 // 业务参数格式错误
+	CodeIPNotAllowed         = 1013 // ip不在白名单内
+	CodeInvalidAppAuthToken  = 1014 // 无效的授权令牌
+	CodeThrottled            = 1015 // 请求过于频繁
+	CodeSystemBusy           = 1016 // 系统繁忙
+	CodeProductNotFound      = 2001 // 商品不存在
+	CodeProductOffline       = 2002 // 商品已下架
+	CodeProductMaintain      = 2003 // 商品维护中
+	CodeProductOutOfStock    = 2004 // 商品库存不足
+	CodePriceMismatch        = 2005 // 商品价格不符
+	CodeInsufficientBalance  = 3001 // 账户余额不足
+	CodeAccountDisabled      = 3002 // 账户已停用
+	CodeDuplicateOrder       = 4001 // 外部订单号重复
+	CodeOrderNotFound        = 4002 // 订单不存在
+	CodeChargeAccountInvalid = 4003 // 充值账号格式错误
+	CodeMobileMaintain       = 4004 // 号段维护中
+)
+
+// 可通过errors.Is判断的错误
+var (
+	ErrHTTPStatus           = errors.New("fulu: unexpected http status")
+	ErrSignMismatch         = errors.New("fulu: sign mismatch")
+	ErrOrderNotFinished     = errors.New("fulu: order not finished")
+	ErrInvalidMethod        = errors.New("fulu: invalid api method")
+	ErrInvalidTimestamp     = errors.New("fulu: invalid or expired timestamp")
+	ErrInvalidAppKey        = errors.New("fulu: invalid app_key")
+	ErrInvalidVersion       = errors.New("fulu: invalid version")
+	ErrSignError            = errors.New("fulu: sign error")
+	ErrInvalidBizContent    = errors.New("fulu: invalid biz_content")
+	ErrIPNotAllowed         = errors.New("fulu: ip not allowed")
+	ErrInvalidAppAuthToken  = errors.New("fulu: invalid app_auth_token")
+	ErrThrottled            = errors.New("fulu: throttled")
+	ErrSystemBusy           = errors.New("fulu: system busy")
+	ErrProductNotFound      = errors.New("fulu: product not found")
+	ErrProductOffline       = errors.New("fulu: product offline")
+	ErrProductMaintain      = errors.New("fulu: product under maintenance")
+	ErrProductOutOfStock    = errors.New("fulu: product out of stock")
+	ErrPriceMismatch        = errors.New("fulu: price mismatch")
+	ErrInsufficientBalance  = errors.New("fulu: insufficient balance")
+	ErrAccountDisabled      = errors.New("fulu: account disabled")
+	ErrDuplicateOrder       = errors.New("fulu: duplicate customer order")
+	ErrOrderNotFound        = errors.New("fulu: order not found")
+	ErrChargeAccountInvalid = errors.New("fulu: invalid charge account")
+	ErrMobileMaintain       = errors.New("fulu: mobile segment under maintenance")
+)
+
+var codeErrors = map[int]error{
+	CodeMissingMethod:        ErrInvalidMethod,
+	CodeInvalidMethod:        ErrInvalidMethod,
+	CodeMissingTimestamp:     ErrInvalidTimestamp,
+	CodeInvalidTimestamp:     ErrInvalidTimestamp,
+	CodeTimestampExpired:     ErrInvalidTimestamp,
+	CodeMissingAppKey:        ErrInvalidAppKey,
+	CodeInvalidAppKey:        ErrInvalidAppKey,
+	CodeMissingVersion:       ErrInvalidVersion,
+	CodeInvalidVersion:       ErrInvalidVersion,
+	CodeMissingSign:          ErrSignError,
+	CodeSignError:            ErrSignError,
+	CodeMissingBizContent:    ErrInvalidBizContent,
+	CodeInvalidBizContent:    ErrInvalidBizContent,
+	CodeIPNotAllowed:         ErrIPNotAllowed,
+	CodeInvalidAppAuthToken:  ErrInvalidAppAuthToken,
+	CodeThrottled:            ErrThrottled,
+	CodeSystemBusy:           ErrSystemBusy,
+	CodeProductNotFound:      ErrProductNotFound,
+	CodeProductOffline:       ErrProductOffline,
+	CodeProductMaintain:      ErrProductMaintain,
+	CodeProductOutOfStock:    ErrProductOutOfStock,
+	CodePriceMismatch:        ErrPriceMismatch,
+	CodeInsufficientBalance:  ErrInsufficientBalance,
+	CodeAccountDisabled:      ErrAccountDisabled,
+	CodeDuplicateOrder:       ErrDuplicateOrder,
+	CodeOrderNotFound:        ErrOrderNotFound,
+	CodeChargeAccountInvalid: ErrChargeAccountInvalid,
+	CodeMobileMaintain:       ErrMobileMaintain,
+}
+
+// CodeError 返回错误码对应的错误，未知错误码返回nil
+func CodeError(code int) error {
+	return codeErrors[code]
+}
+
+// APIError 接口调用错误
+type APIError struct {
+	Method     Method // 接口名称
+	Code       int    // 福禄返回码
+	Message    string // 福禄返回信息
+	StatusCode int    // http状态码
+	Body       []byte // 原始响应内容
+	Err        error  // 底层错误，如响应解析失败
+}
+
+func (e *APIError) Error() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("fulu: api method [%s] failed: %v", e.Method, e.Err)
+	case e.Code != CodeSuccess:
+		return fmt.Sprintf("fulu: api method [%s] errno: %d, errmsg: %s", e.Method, e.Code, e.Message)
+	default:
+		return fmt.Sprintf("fulu: api method [%s] call failed, status code is %d, %s", e.Method, e.StatusCode, http.StatusText(e.StatusCode))
+	}
+}
+
+// Is 支持errors.Is按错误码匹配
+func (e *APIError) Is(target error) bool {
+	if target == ErrHTTPStatus {
+		return e.StatusCode != 0 && (e.StatusCode < 200 || e.StatusCode > 299)
+	}
+	if errors.Is(e.Err, ErrSignMismatch) {
+		return false
+	}
+	if sentinel := codeErrors[e.Code]; sentinel != nil {
+		return sentinel == target
+	}
+	return false
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// isTransientError 是否为可重试的临时错误，单次请求超时视为临时错误，
+// 调用方ctx是否已结束需由调用方另行判断
+func isTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+	switch {
+	case errors.Is(err, ErrOrderNotFound), errors.Is(err, ErrThrottled), errors.Is(err, ErrSystemBusy):
+		return true
+	case apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError:
+		return true
+	}
+	return false
+}
+
+// ErrorCode 从错误中获取福禄返回码，网络错误、非2xx响应、签名不符及响应解析失败等
+// 没有可信返回码的错误返回-1
+func ErrorCode(err error) int {
+	if err == nil {
+		return CodeSuccess
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Code != CodeSuccess {
+		return apiErr.Code
+	}
+	return -1
+}
@@ -0,0 +1,77 @@
+// Package fuluprom 提供福禄sdk的Prometheus指标实现
+package fuluprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"strconv"
+	"time"
+)
+
+// Collector 实现fulu.Metrics及prometheus.Collector
+type Collector struct {
+	callDuration   *prometheus.HistogramVec
+	calls          *prometheus.CounterVec
+	ordersCreated  *prometheus.CounterVec
+	ordersFinished *prometheus.CounterVec
+}
+
+var _ fulu.Metrics = (*Collector)(nil)
+
+// NewCollector 初始化指标收集器，需自行注册到prometheus.Registerer
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		callDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "fulu",
+			Name:      "call_duration_seconds",
+			Help:      "Latency of Fulu API calls by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "fulu",
+			Name:      "calls_total",
+			Help:      "Fulu API calls by method and response code, -1 for transport, non-2xx, sign and decode errors.",
+		}, []string{"method", "code"}),
+		ordersCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "fulu",
+			Name:      "orders_created_total",
+			Help:      "Orders created by product.",
+		}, []string{"product_id"}),
+		ordersFinished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "fulu",
+			Name:      "orders_finished_total",
+			Help:      "Orders reaching a final state by product and state, deduplicated by customer order number per client.",
+		}, []string{"product_id", "state"}),
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.callDuration.Describe(ch)
+	c.calls.Describe(ch)
+	c.ordersCreated.Describe(ch)
+	c.ordersFinished.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.callDuration.Collect(ch)
+	c.calls.Collect(ch)
+	c.ordersCreated.Collect(ch)
+	c.ordersFinished.Collect(ch)
+}
+
+func (c *Collector) ObserveCall(method fulu.Method, code int, latency time.Duration) {
+	c.callDuration.WithLabelValues(string(method)).Observe(latency.Seconds())
+	c.calls.WithLabelValues(string(method), strconv.Itoa(code)).Inc()
+}
+
+func (c *Collector) OrderCreated(productID int64) {
+	c.ordersCreated.WithLabelValues(strconv.FormatInt(productID, 10)).Inc()
+}
+
+func (c *Collector) OrderFinished(productID int64, state fulu.OrderState) {
+	c.ordersFinished.WithLabelValues(strconv.FormatInt(productID, 10), string(state)).Inc()
+}
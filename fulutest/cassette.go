@@ -0,0 +1,287 @@
+package fulutest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	jsoniter "github.com/json-iterator/go"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrInteractionNotFound 回放时没有匹配的录制记录
+var ErrInteractionNotFound = errors.New("fulutest: no recorded interaction matches request")
+
+// Interaction 录制的一次请求及响应，请求及响应内容均已脱敏
+type Interaction struct {
+	Method      fulu.Method `json:"method,omitempty"`
+	BizContent  string      `json:"biz_content,omitempty"`
+	HTTPMethod  string      `json:"http_method"`
+	URL         string      `json:"url"`
+	Request     string      `json:"request"`
+	StatusCode  int         `json:"status_code"`
+	ContentType string      `json:"content_type"`
+	Response    string      `json:"response"`
+	RecordedAt  time.Time   `json:"recorded_at"`
+}
+
+// Cassette 录制的请求集合，并发安全
+type Cassette struct {
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+type cassetteFile struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// LoadCassette 读取录制文件
+func LoadCassette(path string) (*Cassette, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file cassetteFile
+	if err = jsoniter.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("parse cassette %s: %w", path, err)
+	}
+	return &Cassette{interactions: file.Interactions}, nil
+}
+
+// Save 保存录制文件
+func (c *Cassette) Save(path string) error {
+	raw, err := jsoniter.MarshalIndent(cassetteFile{Interactions: c.Interactions()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, 0o644)
+}
+
+// Interactions 录制的全部请求
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction(nil), c.interactions...)
+}
+
+func (c *Cassette) add(interaction Interaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, interaction)
+}
+
+// Recorder 录制请求的http.RoundTripper，通过fulu.NewWithClient接入
+type Recorder struct {
+	transport http.RoundTripper
+	redactor  *fulu.Redactor
+	cassette  *Cassette
+}
+
+// NewRecorder 初始化录制器，transport为nil时使用http.DefaultTransport，
+// redactor为nil时使用默认脱敏规则，AppSecret等明文需通过Redactor.WithSecrets加入
+func NewRecorder(transport http.RoundTripper, redactor *fulu.Redactor) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if redactor == nil {
+		redactor = fulu.NewRedactor(fulu.DefaultRedactRules()...)
+	}
+	return &Recorder{transport: transport, redactor: redactor, cassette: &Cassette{}}
+}
+
+// Cassette 已录制的内容
+func (r *Recorder) Cassette() *Cassette {
+	return r.cassette
+}
+
+// HTTPClient 使用录制器的http.Client
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, req, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	var interaction = Interaction{
+		HTTPMethod:  req.Method,
+		URL:         req.URL.String(),
+		Request:     r.redactor.Redact(string(reqBody)),
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Response:    r.redactor.Redact(string(respBody)),
+		RecordedAt:  time.Now(),
+	}
+	if params, ok := requestParams(reqBody); ok {
+		interaction.Method = params.Method
+		interaction.BizContent = r.redactor.Redact(params.BizContent)
+	}
+	r.cassette.add(interaction)
+	return resp, nil
+}
+
+// Replayer 回放录制内容的http.RoundTripper，接口请求按Method及biz_content匹配，
+// 忽略timestamp及sign；其余请求按http方法及url匹配。
+// 相同请求依次返回录制的响应，用完后重复返回最后一次响应
+type Replayer struct {
+	cassette *Cassette
+	redactor *fulu.Redactor
+	signer   fulu.Signer
+
+	mu   sync.Mutex
+	used []bool
+}
+
+// NewReplayer 初始化回放器，redactor需与录制时一致，为nil时使用默认脱敏规则。
+// 录制时脱敏会使响应签名失效，客户端开启VerifySign时需传入signer重新签名
+func NewReplayer(cassette *Cassette, redactor *fulu.Redactor, signer fulu.Signer) *Replayer {
+	if redactor == nil {
+		redactor = fulu.NewRedactor(fulu.DefaultRedactRules()...)
+	}
+	return &Replayer{
+		cassette: cassette,
+		redactor: redactor,
+		signer:   signer,
+		used:     make([]bool, len(cassette.Interactions())),
+	}
+}
+
+// HTTPClient 使用回放器的http.Client
+func (r *Replayer) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, _, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	var match func(Interaction) bool
+	if params, ok := requestParams(reqBody); ok {
+		bizContent := r.redactor.Redact(params.BizContent)
+		match = func(interaction Interaction) bool {
+			return interaction.Method == params.Method && interaction.BizContent == bizContent
+		}
+	} else {
+		url := req.URL.String()
+		match = func(interaction Interaction) bool {
+			return interaction.Method == "" && interaction.HTTPMethod == req.Method && interaction.URL == url
+		}
+	}
+
+	interaction, ok := r.next(match)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, req.Method, r.redactor.Redact(string(reqBody)))
+	}
+	body := []byte(interaction.Response)
+	if r.signer != nil && interaction.Method != "" {
+		if body, err = r.resign(body); err != nil {
+			return nil, err
+		}
+	}
+	var header = make(http.Header)
+	if interaction.ContentType != "" {
+		header.Set("Content-Type", interaction.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.StatusCode, http.StatusText(interaction.StatusCode)),
+		StatusCode:    interaction.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// next 第一条未使用的匹配记录，全部用完时返回最后一条
+func (r *Replayer) next(match func(Interaction) bool) (Interaction, bool) {
+	interactions := r.cassette.Interactions()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var last = -1
+	for i, interaction := range interactions {
+		if !match(interaction) {
+			continue
+		}
+		if i < len(r.used) && !r.used[i] {
+			r.used[i] = true
+			return interaction, true
+		}
+		last = i
+	}
+	if last < 0 {
+		return Interaction{}, false
+	}
+	return interactions[last], true
+}
+
+// resign 重新计算响应签名，非福禄响应格式时原样返回
+func (r *Replayer) resign(body []byte) ([]byte, error) {
+	var resp fulu.RespData
+	if err := jsoniter.Unmarshal(body, &resp); err != nil {
+		return body, nil
+	}
+	resp.Sign = ""
+	payload, err := fulu.SignPayload(resp)
+	if err != nil {
+		return nil, err
+	}
+	resp.Sign, err = r.signer.Sign(payload)
+	if err != nil {
+		return nil, err
+	}
+	return jsoniter.Marshal(resp)
+}
+
+// readRequestBody 读取请求内容，返回可再次读取的请求副本
+func readRequestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	clone.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, clone, nil
+}
+
+// requestParams 解析福禄接口请求的公共参数
+func requestParams(body []byte) (*fulu.ReqParams, bool) {
+	if len(body) == 0 {
+		return nil, false
+	}
+	var params fulu.ReqParams
+	if err := jsoniter.Unmarshal(body, &params); err != nil || params.Method == "" {
+		return nil, false
+	}
+	return &params, true
+}
@@ -0,0 +1,201 @@
+package fulutest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	jsoniter "github.com/json-iterator/go"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"github.com/t2krew/fulu-gosdk/fulutest"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	appAuthToken   = "auth-token-secret-1234"
+	chargePassword = "charge-pass-5678"
+	cardPwd        = "card-pwd-9012"
+)
+
+// captureTransport 记录经过的请求内容
+type captureTransport struct {
+	transport http.RoundTripper
+
+	mu     sync.Mutex
+	params []fulu.ReqParams
+}
+
+func (c *captureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		var params fulu.ReqParams
+		if jsoniter.Unmarshal(body, &params) == nil && params.Method != "" {
+			c.mu.Lock()
+			c.params = append(c.params, params)
+			c.mu.Unlock()
+		}
+	}
+	return c.transport.RoundTrip(req)
+}
+
+// runScenario 依次调用下单、查单及账户接口
+func runScenario(t *testing.T, client *fulu.Client) (*fulu.Order, *fulu.AccountInfo) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := client.CreateDirectOrder(ctx, fulu.CreateDirectOrderBizContent{
+		ProductID:      1001,
+		CustomerOrder:  "direct-001",
+		ChargeAccount:  "player-001",
+		BuyNum:         1,
+		ChargePassword: chargePassword,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateCardOrder(ctx, fulu.CreateCardOrderBizContent{ProductID: 1002, CustomerOrderNO: "card-001", BuyNum: 1}); err != nil {
+		t.Fatal(err)
+	}
+	order, err := client.QueryOrder(ctx, "card-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := client.GetAccountInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return order, account
+}
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	srv := fulutest.NewServer()
+	defer srv.Close()
+	srv.SetBalance(100)
+	srv.SeedProducts(
+		fulu.ProductInfo{ProductID: 1001, ProductName: "游戏直充", PurchasePrice: 10},
+		fulu.ProductInfo{ProductID: 1002, ProductName: "卡密", PurchasePrice: 10},
+	)
+	srv.SetOrderLifecycle("card-001", fulutest.Lifecycle{
+		Cards: []fulu.CardItem{{CardType: 1, CardNumber: "card-no-001", CardPwd: cardPwd}},
+	})
+
+	cfg := srv.Config()
+	cfg.AppAuthToken = appAuthToken
+	if !cfg.VerifySign {
+		t.Fatal("fulutest config should verify response signs")
+	}
+	redactor := fulu.NewRedactor(fulu.DefaultRedactRules()...).WithSecrets(cfg.AppSecret, cfg.AppAuthToken)
+
+	recorder := fulutest.NewRecorder(nil, redactor)
+	client, err := fulu.NewWithClient(cfg, recorder.HTTPClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordedOrder, recordedAccount := runScenario(t, client)
+	if len(recordedOrder.Cards) != 1 || recordedOrder.Cards[0].CardPwd != cardPwd {
+		t.Fatalf("recorded order cards = %+v, want the live card password", recordedOrder.Cards)
+	}
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err = recorder.Cassette().Save(path); err != nil {
+		t.Fatal(err)
+	}
+	cassette, err := fulutest.LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interactions := cassette.Interactions()
+	if len(interactions) != 4 {
+		t.Fatalf("recorded %d interactions, want 4", len(interactions))
+	}
+	var recorded = make(map[fulu.Method]fulu.ReqParams)
+	for _, interaction := range interactions {
+		for _, secret := range []string{cfg.AppSecret, appAuthToken, chargePassword, cardPwd} {
+			for field, content := range map[string]string{
+				"request":     interaction.Request,
+				"response":    interaction.Response,
+				"biz_content": interaction.BizContent,
+			} {
+				if strings.Contains(content, secret) {
+					t.Fatalf("%s %s contains secret %q: %s", interaction.Method, field, secret, content)
+				}
+			}
+		}
+		var params fulu.ReqParams
+		if err = jsoniter.UnmarshalFromString(interaction.Request, &params); err != nil {
+			t.Fatal(err)
+		}
+		recorded[params.Method] = params
+	}
+
+	// 回放时间戳必然不同，签名随之变化
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+
+	signer, err := fulu.NewSigner(cfg.SignType, cfg.AppSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	capture := &captureTransport{transport: fulutest.NewReplayer(cassette, redactor, signer)}
+	client, err = fulu.NewWithClient(cfg, &http.Client{Transport: capture})
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayedOrder, replayedAccount := runScenario(t, client)
+
+	if replayedOrder.OrderID != recordedOrder.OrderID || replayedOrder.OrderState != recordedOrder.OrderState {
+		t.Fatalf("replayed order = %+v, want %+v", replayedOrder, recordedOrder)
+	}
+	if len(replayedOrder.Cards) != 1 || replayedOrder.Cards[0].CardPwd == cardPwd {
+		t.Fatalf("replayed order cards = %+v, want redacted card password", replayedOrder.Cards)
+	}
+	if *replayedAccount != *recordedAccount {
+		t.Fatalf("replayed account = %+v, want %+v", replayedAccount, recordedAccount)
+	}
+	if len(capture.params) != len(recorded) {
+		t.Fatalf("replayed %d requests, want %d", len(capture.params), len(recorded))
+	}
+	for _, params := range capture.params {
+		if params.Timestamp == recorded[params.Method].Timestamp || params.Sign == recorded[params.Method].Sign {
+			t.Fatalf("%s replayed with recorded timestamp or sign", params.Method)
+		}
+	}
+}
+
+func TestReplayerRequiresSignerForVerifySign(t *testing.T) {
+	srv := fulutest.NewServer()
+	defer srv.Close()
+	srv.SetBalance(100)
+	srv.SeedOrders(fulu.Order{
+		CustomerOrderNO: "card-001",
+		ProductID:       1002,
+		Cards:           []fulu.CardItem{{CardType: 1, CardNumber: "card-no-001", CardPwd: cardPwd}},
+	})
+
+	cfg := srv.Config()
+	redactor := fulu.NewRedactor(fulu.DefaultRedactRules()...).WithSecrets(cfg.AppSecret)
+	recorder := fulutest.NewRecorder(nil, redactor)
+	client, err := fulu.NewWithClient(cfg, recorder.HTTPClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.QueryOrder(context.Background(), "card-001"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 卡密脱敏后响应签名失效，未传入signer时开启VerifySign的客户端拒绝回放内容
+	client, err = fulu.NewWithClient(cfg, fulutest.NewReplayer(recorder.Cassette(), redactor, nil).HTTPClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.QueryOrder(context.Background(), "card-001"); !errors.Is(err, fulu.ErrSignMismatch) {
+		t.Fatalf("QueryOrder() error = %v, want ErrSignMismatch", err)
+	}
+}
@@ -0,0 +1,144 @@
+package fulutest
+
+import (
+	fulu "github.com/t2krew/fulu-gosdk"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+// FaultKind 故障类型
+type FaultKind string
+
+const (
+	FaultNone          = FaultKind("")               // 无故障
+	FaultLatency       = FaultKind("latency")        // 仅延迟响应
+	FaultConnReset     = FaultKind("conn_reset")     // 重置连接
+	FaultHTTPStatus    = FaultKind("http_status")    // 返回非2xx状态码
+	FaultMalformedJSON = FaultKind("malformed_json") // 返回不完整的json
+	FaultEmptyResult   = FaultKind("empty_result")   // 成功响应的result为空
+	FaultBadSign       = FaultKind("bad_sign")       // 响应签名错误
+	FaultCode          = FaultKind("code")           // 返回业务错误码，默认为限流
+)
+
+// Fault 注入的故障
+type Fault struct {
+	Kind       FaultKind     `json:"kind"`
+	Latency    time.Duration `json:"latency"`     // 响应前的延迟，可与其他故障类型同时使用
+	StatusCode int           `json:"status_code"` // FaultHTTPStatus的状态码，默认503
+	Code       int           `json:"code"`        // FaultCode的错误码，默认CodeThrottled
+	// Processed 接口处理完成后再注入FaultConnReset、FaultHTTPStatus或FaultCode，
+	// 用于模拟订单已创建但响应丢失。其余故障类型总是在处理完成后注入
+	Processed bool `json:"processed"`
+}
+
+// FaultRule 按概率注入故障，Method为空时匹配所有接口
+type FaultRule struct {
+	Method      fulu.Method `json:"method"`
+	Probability float64     `json:"probability"`
+	Fault       Fault       `json:"fault"`
+}
+
+// WithFaultSeed 设置按概率注入故障使用的随机数种子
+func WithFaultSeed(seed int64) Option {
+	return func(g *Gateway) {
+		g.rand = rand.New(rand.NewSource(seed))
+	}
+}
+
+// AddFaultRule 添加按概率注入的故障，按添加顺序匹配第一个命中的规则
+func (g *Gateway) AddFaultRule(rules ...FaultRule) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.faultRules = append(g.faultRules, rules...)
+}
+
+// ScriptFaults 为接口后续的请求依次注入故障，Kind为FaultNone时该次请求正常处理，优先于概率规则
+func (g *Gateway) ScriptFaults(method fulu.Method, faults ...Fault) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.faultScripts[method] = append(g.faultScripts[method], faults...)
+}
+
+// ClearFaults 清除所有故障配置
+func (g *Gateway) ClearFaults() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.faultRules = nil
+	g.faultScripts = make(map[fulu.Method][]Fault)
+}
+
+// nextFault 本次请求注入的故障
+func (g *Gateway) nextFault(method fulu.Method) Fault {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if script := g.faultScripts[method]; len(script) > 0 {
+		g.faultScripts[method] = script[1:]
+		return script[0]
+	}
+	for _, rule := range g.faultRules {
+		if rule.Method != "" && rule.Method != method {
+			continue
+		}
+		if g.rand.Float64() < rule.Probability {
+			return rule.Fault
+		}
+	}
+	return Fault{}
+}
+
+// interrupts 故障是否替代正常响应
+func (f Fault) interrupts() bool {
+	return f.Kind == FaultConnReset || f.Kind == FaultHTTPStatus || f.Kind == FaultCode
+}
+
+// inject 注入替代正常响应的故障
+func (g *Gateway) inject(w http.ResponseWriter, call *Call, fault Fault) {
+	switch fault.Kind {
+	case FaultConnReset:
+		g.record(call, -1, "connection reset")
+		resetConn(w)
+	case FaultHTTPStatus:
+		var status = fault.StatusCode
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		g.record(call, -1, http.StatusText(status))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(http.StatusText(status)))
+	case FaultCode:
+		var code = fault.Code
+		if code == 0 {
+			code = fulu.CodeThrottled
+		}
+		g.writeResponse(w, call, nil, NewError(code, ""), fault)
+	}
+}
+
+// resetConn 关闭连接并发送RST
+func resetConn(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		_ = tcpConn.SetLinger(0)
+	}
+	_ = conn.Close()
+}
+
+func sleepRequest(r *http.Request, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
@@ -0,0 +1,433 @@
+// Package fulutest 提供用于测试的福禄网关模拟服务
+package fulutest
+
+import (
+	"errors"
+	"fmt"
+	jsoniter "github.com/json-iterator/go"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 模拟网关默认凭证
+const (
+	DefaultAppKey    = "fulutest-app-key"
+	DefaultAppSecret = "fulutest-app-secret"
+)
+
+const (
+	defaultTimestampWindow = 10 * time.Minute
+	defaultAccountName     = "fulutest"
+	maxRequestBodySize     = 1 << 20
+)
+
+// Error 网关返回的错误码，处理函数返回*Error时作为福禄业务错误响应
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("fulutest: code %d: %s", e.Code, e.Message)
+}
+
+// NewError 创建网关错误，message为空时使用默认描述
+func NewError(code int, message string) *Error {
+	if message == "" {
+		message = codeMessages[code]
+	}
+	return &Error{Code: code, Message: message}
+}
+
+var codeMessages = map[int]string{
+	fulu.CodeSuccess:              "接口调用成功",
+	fulu.CodeMissingMethod:        "必须传入API接口名称",
+	fulu.CodeInvalidMethod:        "无效的API接口名称",
+	fulu.CodeMissingTimestamp:     "必须传入时间戳",
+	fulu.CodeInvalidTimestamp:     "时间戳格式错误",
+	fulu.CodeTimestampExpired:     "时间戳已超过有效期",
+	fulu.CodeMissingAppKey:        "必须传入app_key",
+	fulu.CodeInvalidAppKey:        "无效的app_key",
+	fulu.CodeMissingVersion:       "必须传入版本号",
+	fulu.CodeInvalidVersion:       "无效的版本号",
+	fulu.CodeMissingSign:          "必须传入签名",
+	fulu.CodeSignError:            "无效签名",
+	fulu.CodeMissingBizContent:    "必须传入业务参数",
+	fulu.CodeInvalidBizContent:    "业务参数格式错误",
+	fulu.CodeIPNotAllowed:         "ip不在白名单内",
+	fulu.CodeInvalidAppAuthToken:  "无效的授权令牌",
+	fulu.CodeThrottled:            "请求过于频繁",
+	fulu.CodeSystemBusy:           "系统繁忙",
+	fulu.CodeProductNotFound:      "商品不存在",
+	fulu.CodeProductOffline:       "商品已下架",
+	fulu.CodeProductMaintain:      "商品维护中",
+	fulu.CodeProductOutOfStock:    "商品库存不足",
+	fulu.CodePriceMismatch:        "商品价格不符",
+	fulu.CodeInsufficientBalance:  "账户余额不足",
+	fulu.CodeAccountDisabled:      "账户已停用",
+	fulu.CodeDuplicateOrder:       "外部订单号重复",
+	fulu.CodeOrderNotFound:        "订单不存在",
+	fulu.CodeChargeAccountInvalid: "充值账号格式错误",
+	fulu.CodeMobileMaintain:       "号段维护中",
+}
+
+// HandlerFunc 接口处理函数，bizContent为请求中的biz_content，返回值序列化后作为result
+type HandlerFunc func(bizContent []byte) (interface{}, error)
+
+// Call 网关收到的请求
+type Call struct {
+	Method     fulu.Method    `json:"method"`
+	Params     fulu.ReqParams `json:"params"`
+	BizContent string         `json:"biz_content"`
+	Code       int            `json:"code"` // -1为连接重置或非2xx响应
+	Message    string         `json:"message"`
+	Fault      FaultKind      `json:"fault,omitempty"`
+	Time       time.Time      `json:"time"`
+}
+
+// Option 模拟网关配置
+type Option func(*Gateway)
+
+// WithCredentials 设置网关校验的AppKey及AppSecret
+func WithCredentials(appKey, appSecret string) Option {
+	return func(g *Gateway) {
+		g.appKey = appKey
+		g.appSecret = appSecret
+	}
+}
+
+// WithSignType 设置签名类型，默认md5
+func WithSignType(signType string) Option {
+	return func(g *Gateway) {
+		g.signType = signType
+	}
+}
+
+// WithTimestampWindow 设置请求时间戳允许的误差，默认10分钟，小于0时不校验
+func WithTimestampWindow(window time.Duration) Option {
+	return func(g *Gateway) {
+		g.timestampWindow = window
+	}
+}
+
+// WithCallHook 设置收到请求后的回调，在响应前同步调用
+func WithCallHook(fn func(call Call)) Option {
+	return func(g *Gateway) {
+		g.callHook = fn
+	}
+}
+
+// Gateway 内存中的福禄网关，实现了client.go中的全部接口，并发安全
+type Gateway struct {
+	appKey          string
+	appSecret       string
+	signType        string
+	signer          fulu.Signer
+	timestampWindow time.Duration
+	callHook        func(call Call)
+
+	mu              sync.Mutex
+	handlers        map[fulu.Method]HandlerFunc
+	products        map[int64]fulu.ProductInfo
+	templates       map[string]fulu.ProductTemplate
+	qqNicknames     map[string]fulu.GetQQNicknameResult
+	accountName     string
+	balance         float64
+	accountDisabled bool
+	orders          map[string]*order
+	orderSeq        int64
+	tasks           map[string]*reconciliationTask
+	taskSeq         int64
+	calls           []Call
+
+	productLifecycles map[int64]Lifecycle
+	orderLifecycles   map[string]Lifecycle
+	timers            []*time.Timer
+	closed            bool
+	notifyURL         string
+	notifyClient      *http.Client
+	notifications     []Notification
+	notifying         sync.WaitGroup
+
+	rand         *rand.Rand
+	faultRules   []FaultRule
+	faultScripts map[fulu.Method][]Fault
+}
+
+// NewGateway 初始化模拟网关
+func NewGateway(opts ...Option) (*Gateway, error) {
+	var g = &Gateway{
+		appKey:          DefaultAppKey,
+		appSecret:       DefaultAppSecret,
+		signType:        fulu.SignTypeMD5,
+		timestampWindow: defaultTimestampWindow,
+		products:        make(map[int64]fulu.ProductInfo),
+		templates:       make(map[string]fulu.ProductTemplate),
+		qqNicknames:     make(map[string]fulu.GetQQNicknameResult),
+		accountName:     defaultAccountName,
+		orders:          make(map[string]*order),
+		tasks:           make(map[string]*reconciliationTask),
+
+		productLifecycles: make(map[int64]Lifecycle),
+		orderLifecycles:   make(map[string]Lifecycle),
+		notifyClient:      &http.Client{Timeout: 5 * time.Second},
+
+		rand:         rand.New(rand.NewSource(time.Now().UnixNano())),
+		faultScripts: make(map[fulu.Method][]Fault),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.appKey == "" || g.appSecret == "" {
+		return nil, errors.New("appkey or appsecret is empty")
+	}
+	signer, err := fulu.NewSigner(g.signType, g.appSecret)
+	if err != nil {
+		return nil, err
+	}
+	g.signer = signer
+	g.handlers = map[fulu.Method]HandlerFunc{
+		fulu.MethodGetProductList:          g.getProductList,
+		fulu.MethodGetProductInfo:          g.getProductInfo,
+		fulu.MethodGetProductTemplate:      g.getProductTemplate,
+		fulu.MethodCheckProductStock:       g.checkProductStock,
+		fulu.MethodGetAccountInfo:          g.getAccountInfo,
+		fulu.MethodGetQQNickname:           g.getQQNickname,
+		fulu.MethodGetMobileInfo:           g.getMobileInfo,
+		fulu.MethodGetMobileMaintainStatus: g.getMobileMaintainStatus,
+		fulu.MethodCreateDirectOrder:       g.createDirectOrder,
+		fulu.MethodCreateCardOrder:         g.createCardOrder,
+		fulu.MethodCreateMobileOrder:       g.createMobileOrder,
+		fulu.MethodQueryOrder:              g.queryOrder,
+		fulu.MethodQueryOrderExtend:        g.queryOrderExtend,
+		fulu.MethodApplyReconciliation:     g.applyReconciliation,
+		fulu.MethodGetReconciliation:       g.getReconciliation,
+	}
+	return g, nil
+}
+
+// Config 访问该网关的客户端配置
+func (g *Gateway) Config(endpoint string) fulu.Config {
+	return fulu.Config{
+		Endpoint:   endpoint,
+		AppKey:     g.appKey,
+		AppSecret:  g.appSecret,
+		SignType:   g.signType,
+		VerifySign: true,
+	}
+}
+
+// Signer 网关使用的签名器
+func (g *Gateway) Signer() fulu.Signer {
+	return g.signer
+}
+
+// Handle 注册或覆盖接口处理函数，处理函数在网关锁外调用
+func (g *Gateway) Handle(method fulu.Method, fn HandlerFunc) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.handlers[method] = fn
+}
+
+// Calls 网关收到的请求，指定method时只返回对应接口的请求
+func (g *Gateway) Calls(methods ...fulu.Method) []Call {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var calls = make([]Call, 0, len(g.calls))
+	for _, call := range g.calls {
+		if len(methods) == 0 || containsMethod(methods, call.Method) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// ResetCalls 清空请求记录
+func (g *Gateway) ResetCalls() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.calls = nil
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, reconciliationPath) {
+		g.serveReconciliation(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	raw, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var params fulu.ReqParams
+	_ = jsoniter.Unmarshal(raw, &params)
+	var (
+		fault = g.nextFault(params.Method)
+		call  = Call{
+			Method:     params.Method,
+			Params:     params,
+			BizContent: params.BizContent,
+			Fault:      fault.Kind,
+			Time:       time.Now(),
+		}
+	)
+	if fault.Latency > 0 && !sleepRequest(r, fault.Latency) {
+		return
+	}
+	if fault.interrupts() && !fault.Processed {
+		g.inject(w, &call, fault)
+		return
+	}
+
+	result, err := g.dispatch(r, raw, &params)
+	if fault.interrupts() {
+		g.inject(w, &call, fault)
+		return
+	}
+	g.writeResponse(w, &call, result, err, fault)
+}
+
+// dispatch 校验公共参数及签名后调用接口处理函数
+func (g *Gateway) dispatch(r *http.Request, raw []byte, params *fulu.ReqParams) (interface{}, error) {
+	if err := g.validate(raw, params); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	handler, ok := g.handlers[params.Method]
+	g.mu.Unlock()
+	if !ok {
+		return nil, NewError(fulu.CodeInvalidMethod, "")
+	}
+	result, err := handler([]byte(params.BizContent))
+	if task, ok := result.(*fulu.ReconciliationTask); ok && task.Status == fulu.ReconciliationStatusSuccess {
+		task.DownloadURL = requestBaseURL(r) + task.DownloadURL
+	}
+	return result, err
+}
+
+func (g *Gateway) validate(raw []byte, params *fulu.ReqParams) error {
+	if !jsoniter.Valid(raw) {
+		return NewError(fulu.CodeInvalidBizContent, "请求内容格式错误")
+	}
+	switch {
+	case params.Method == "":
+		return NewError(fulu.CodeMissingMethod, "")
+	case params.Timestamp == "":
+		return NewError(fulu.CodeMissingTimestamp, "")
+	case params.AppKey == "":
+		return NewError(fulu.CodeMissingAppKey, "")
+	case params.AppKey != g.appKey:
+		return NewError(fulu.CodeInvalidAppKey, "")
+	case params.Version == "":
+		return NewError(fulu.CodeMissingVersion, "")
+	case params.Version != "2.0":
+		return NewError(fulu.CodeInvalidVersion, "")
+	case params.Sign == "":
+		return NewError(fulu.CodeMissingSign, "")
+	case params.BizContent == "":
+		return NewError(fulu.CodeMissingBizContent, "")
+	}
+
+	timestamp, err := time.ParseInLocation(fulu.TimestampFormat, params.Timestamp, time.Local)
+	if err != nil {
+		return NewError(fulu.CodeInvalidTimestamp, "")
+	}
+	if g.timestampWindow >= 0 {
+		if skew := time.Since(timestamp); skew > g.timestampWindow || skew < -g.timestampWindow {
+			return NewError(fulu.CodeTimestampExpired, "")
+		}
+	}
+	if err = fulu.VerifySign(g.signer, raw); err != nil {
+		return NewError(fulu.CodeSignError, "")
+	}
+	if !jsoniter.Valid([]byte(params.BizContent)) {
+		return NewError(fulu.CodeInvalidBizContent, "")
+	}
+	return nil
+}
+
+func (g *Gateway) writeResponse(w http.ResponseWriter, call *Call, result interface{}, err error, fault Fault) {
+	var resp = fulu.RespData{Code: fulu.CodeSuccess, Message: codeMessages[fulu.CodeSuccess]}
+	if err != nil {
+		var gwErr *Error
+		if !errors.As(err, &gwErr) {
+			gwErr = NewError(fulu.CodeSystemBusy, err.Error())
+		}
+		resp.Code = gwErr.Code
+		resp.Message = gwErr.Message
+	} else if fault.Kind != FaultEmptyResult {
+		resp.Result, err = jsoniter.MarshalToString(result)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+	g.record(call, resp.Code, resp.Message)
+
+	resp.Sign, err = g.responseSign(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if fault.Kind == FaultBadSign {
+		resp.Sign = strings.Repeat("0", len(resp.Sign))
+	}
+	body, err := jsoniter.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if fault.Kind == FaultMalformedJSON {
+		body = body[:len(body)/2]
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_, _ = w.Write(body)
+}
+
+func (g *Gateway) record(call *Call, code int, message string) {
+	call.Code = code
+	call.Message = message
+	g.mu.Lock()
+	g.calls = append(g.calls, *call)
+	g.mu.Unlock()
+	if g.callHook != nil {
+		g.callHook(*call)
+	}
+}
+
+// responseSign 使用与客户端相同的签名算法对响应签名
+func (g *Gateway) responseSign(resp fulu.RespData) (string, error) {
+	resp.Sign = ""
+	payload, err := fulu.SignPayload(resp)
+	if err != nil {
+		return "", err
+	}
+	return g.signer.Sign(payload)
+}
+
+func requestBaseURL(r *http.Request) string {
+	var scheme = "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+func containsMethod(methods []fulu.Method, method fulu.Method) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
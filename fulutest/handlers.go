@@ -0,0 +1,598 @@
+package fulutest
+
+import (
+	"fmt"
+	jsoniter "github.com/json-iterator/go"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 模拟的订单类型
+const (
+	orderTypeDirect = 1 // 直充
+	orderTypeCard   = 2 // 卡密
+	orderTypeMobile = 3 // 话费
+)
+
+// ProductTypeMobile 话费商品类型，话费订单按面值匹配该类型的商品
+const ProductTypeMobile = "话费"
+
+type order struct {
+	fulu.Order
+	ExternalBizID       string
+	RechargeDescription string
+	createdAt           time.Time
+	lifecycle           Lifecycle
+	polls               int // 处理中时被查询的次数
+}
+
+// SeedProducts 添加或覆盖商品，销售状态及库存状态为空时视为上架、充足
+func (g *Gateway) SeedProducts(products ...fulu.ProductInfo) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, product := range products {
+		if product.SalesStatus == "" {
+			product.SalesStatus = string(fulu.SaleStatusValid)
+		}
+		if product.StockStatus == "" {
+			product.StockStatus = string(fulu.StockStatusEnough)
+		}
+		g.products[product.ProductID] = product
+	}
+}
+
+// Products 全部商品，按商品编号排列
+func (g *Gateway) Products() []fulu.ProductInfo {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var products = make([]fulu.ProductInfo, 0, len(g.products))
+	for _, product := range g.products {
+		products = append(products, product)
+	}
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].ProductID < products[j].ProductID
+	})
+	return products
+}
+
+// SetProductStatus 修改商品销售状态及库存状态，为空时不修改
+func (g *Gateway) SetProductStatus(productID int64, sales fulu.SaleStatus, stock fulu.StockStatus) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	product, ok := g.products[productID]
+	if !ok {
+		return false
+	}
+	if sales != "" {
+		product.SalesStatus = string(sales)
+	}
+	if stock != "" {
+		product.StockStatus = string(stock)
+	}
+	g.products[productID] = product
+	return true
+}
+
+// SeedTemplates 添加或覆盖商品模板，按AddressID查询
+func (g *Gateway) SeedTemplates(templates ...fulu.ProductTemplate) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, template := range templates {
+		g.templates[template.AddressID] = template
+	}
+}
+
+// SeedQQNickname 设置qq昵称，未设置的qq号返回默认昵称
+func (g *Gateway) SeedQQNickname(qq string, result fulu.GetQQNicknameResult) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.qqNicknames[qq] = result
+}
+
+// SetBalance 设置账户余额
+func (g *Gateway) SetBalance(balance float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.balance = balance
+}
+
+// Balance 当前账户余额
+func (g *Gateway) Balance() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.balance
+}
+
+// SetAccountDisabled 停用账户后下单返回账户已停用
+func (g *Gateway) SetAccountDisabled(disabled bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.accountDisabled = disabled
+}
+
+// SeedOrders 添加或覆盖订单，用于模拟历史订单，订单状态为空时视为成功
+func (g *Gateway) SeedOrders(orders ...fulu.Order) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, o := range orders {
+		createdAt, err := time.ParseInLocation(fulu.TimestampFormat, o.CreateTime, time.Local)
+		if err != nil {
+			createdAt = time.Now()
+			o.CreateTime = createdAt.Format(fulu.TimestampFormat)
+		}
+		if o.OrderID == "" {
+			o.OrderID = g.nextOrderID()
+		}
+		if o.OrderState == "" {
+			o.OrderState = fulu.OrderStateSuccess
+		}
+		g.orders[o.CustomerOrderNO] = &order{Order: o, createdAt: createdAt}
+	}
+}
+
+// Order 按外部订单号查看订单
+func (g *Gateway) Order(customerOrderNO string) (fulu.Order, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	o, ok := g.orders[customerOrderNO]
+	if !ok {
+		return fulu.Order{}, false
+	}
+	return o.Order, true
+}
+
+// Orders 全部订单，按创建顺序排列
+func (g *Gateway) Orders() []fulu.Order {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var orders = make([]*order, 0, len(g.orders))
+	for _, o := range g.orders {
+		orders = append(orders, o)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].createdAt.Equal(orders[j].createdAt) {
+			return orders[i].OrderID < orders[j].OrderID
+		}
+		return orders[i].createdAt.Before(orders[j].createdAt)
+	})
+	var result = make([]fulu.Order, 0, len(orders))
+	for _, o := range orders {
+		result = append(result, o.Order)
+	}
+	return result
+}
+
+func (g *Gateway) nextOrderID() string {
+	g.orderSeq++
+	return fmt.Sprintf("%s%06d", time.Now().Format("060102"), g.orderSeq)
+}
+
+func decodeBizContent(bizContent []byte, v interface{}) error {
+	if err := jsoniter.Unmarshal(bizContent, v); err != nil {
+		return NewError(fulu.CodeInvalidBizContent, "")
+	}
+	return nil
+}
+
+func parseProductID(productID string) (int64, error) {
+	id, err := strconv.ParseInt(productID, 10, 64)
+	if err != nil {
+		return 0, NewError(fulu.CodeInvalidBizContent, "product_id格式错误")
+	}
+	return id, nil
+}
+
+func (g *Gateway) getProductList(bizContent []byte) (interface{}, error) {
+	var params fulu.GetProductListParams
+	if err := decodeBizContent(bizContent, &params); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var items = make([]fulu.ProductListItem, 0, len(g.products))
+	for _, p := range g.products {
+		switch {
+		case params.ProductID != 0 && p.ProductID != params.ProductID,
+			params.ProductName != "" && !strings.Contains(p.ProductName, params.ProductName),
+			params.ProductType != "" && p.ProductType != params.ProductType,
+			params.FaceValue != 0 && p.FaceValue != params.FaceValue:
+			continue
+		}
+		items = append(items, fulu.ProductListItem{
+			ProductID:     p.ProductID,
+			ProductName:   p.ProductName,
+			ProductType:   p.ProductType,
+			FaceValue:     p.FaceValue,
+			PurchasePrice: p.PurchasePrice,
+			SalesStatus:   p.SalesStatus,
+			StockStatus:   p.StockStatus,
+			TemplateID:    p.TemplateID,
+			Details:       p.Details,
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ProductID < items[j].ProductID
+	})
+	return items, nil
+}
+
+func (g *Gateway) getProductInfo(bizContent []byte) (interface{}, error) {
+	var params fulu.GetProductInfoParams
+	if err := decodeBizContent(bizContent, &params); err != nil {
+		return nil, err
+	}
+	id, err := parseProductID(params.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	product, ok := g.products[id]
+	if !ok {
+		return nil, NewError(fulu.CodeProductNotFound, "")
+	}
+	product.DetailType = params.DetailFormat
+	return product, nil
+}
+
+func (g *Gateway) getProductTemplate(bizContent []byte) (interface{}, error) {
+	var params fulu.GetProductTemplateParams
+	if err := decodeBizContent(bizContent, &params); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	template, ok := g.templates[params.TemplateID]
+	if !ok {
+		return nil, NewError(fulu.CodeProductNotFound, "商品模板不存在")
+	}
+	return template, nil
+}
+
+func (g *Gateway) checkProductStock(bizContent []byte) (interface{}, error) {
+	var params fulu.CheckProductStockParams
+	if err := decodeBizContent(bizContent, &params); err != nil {
+		return nil, err
+	}
+	id, err := parseProductID(params.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	product, ok := g.products[id]
+	if !ok {
+		return nil, NewError(fulu.CodeProductNotFound, "")
+	}
+	return fulu.CheckProductStockResult{StockStatus: product.StockStatus, ProductID: int(id)}, nil
+}
+
+func (g *Gateway) getAccountInfo([]byte) (interface{}, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var isOpen = 1
+	if g.accountDisabled {
+		isOpen = 0
+	}
+	return fulu.AccountInfo{Name: g.accountName, Balance: g.balance, IsOpen: isOpen}, nil
+}
+
+func (g *Gateway) getQQNickname(bizContent []byte) (interface{}, error) {
+	var params struct {
+		QQ string `json:"qq"`
+	}
+	if err := decodeBizContent(bizContent, &params); err != nil {
+		return nil, err
+	}
+	if _, err := strconv.ParseUint(params.QQ, 10, 64); err != nil || len(params.QQ) < 5 {
+		return nil, NewError(fulu.CodeChargeAccountInvalid, "")
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if result, ok := g.qqNicknames[params.QQ]; ok {
+		return result, nil
+	}
+	return fulu.GetQQNicknameResult{Nickname: "QQ用户" + params.QQ}, nil
+}
+
+// mobileSegments 号段对应的运营商
+var mobileSegments = map[string]fulu.MobileSpType{
+	"134": fulu.SpChinaMobile, "135": fulu.SpChinaMobile, "136": fulu.SpChinaMobile, "137": fulu.SpChinaMobile,
+	"138": fulu.SpChinaMobile, "139": fulu.SpChinaMobile, "147": fulu.SpChinaMobile, "150": fulu.SpChinaMobile,
+	"151": fulu.SpChinaMobile, "152": fulu.SpChinaMobile, "157": fulu.SpChinaMobile, "158": fulu.SpChinaMobile,
+	"159": fulu.SpChinaMobile, "178": fulu.SpChinaMobile, "182": fulu.SpChinaMobile, "183": fulu.SpChinaMobile,
+	"184": fulu.SpChinaMobile, "187": fulu.SpChinaMobile, "188": fulu.SpChinaMobile, "198": fulu.SpChinaMobile,
+	"130": fulu.SpChinaUnicom, "131": fulu.SpChinaUnicom, "132": fulu.SpChinaUnicom, "145": fulu.SpChinaUnicom,
+	"155": fulu.SpChinaUnicom, "156": fulu.SpChinaUnicom, "166": fulu.SpChinaUnicom, "175": fulu.SpChinaUnicom,
+	"176": fulu.SpChinaUnicom, "185": fulu.SpChinaUnicom, "186": fulu.SpChinaUnicom,
+	"133": fulu.SpChinaTelecom, "149": fulu.SpChinaTelecom, "153": fulu.SpChinaTelecom, "173": fulu.SpChinaTelecom,
+	"177": fulu.SpChinaTelecom, "180": fulu.SpChinaTelecom, "181": fulu.SpChinaTelecom, "189": fulu.SpChinaTelecom,
+	"191": fulu.SpChinaTelecom, "199": fulu.SpChinaTelecom,
+}
+
+var spNames = map[fulu.MobileSpType]string{
+	fulu.SpChinaMobile:  "移动",
+	fulu.SpChinaTelecom: "电信",
+	fulu.SpChinaUnicom:  "联通",
+}
+
+func mobileSp(phone string) (fulu.MobileSpType, error) {
+	if len(phone) != 11 {
+		return "", NewError(fulu.CodeChargeAccountInvalid, "")
+	}
+	if _, err := strconv.ParseUint(phone, 10, 64); err != nil {
+		return "", NewError(fulu.CodeChargeAccountInvalid, "")
+	}
+	sp, ok := mobileSegments[phone[:3]]
+	if !ok {
+		return "", NewError(fulu.CodeChargeAccountInvalid, "")
+	}
+	return sp, nil
+}
+
+func (g *Gateway) getMobileInfo(bizContent []byte) (interface{}, error) {
+	var params fulu.GetMobileInfoReqParams
+	if err := decodeBizContent(bizContent, &params); err != nil {
+		return nil, err
+	}
+	sp, err := mobileSp(params.Phone)
+	if err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var faceValues = []float64{}
+	for _, p := range g.products {
+		if p.ProductType == ProductTypeMobile && (params.FaceValue == 0 || p.FaceValue == params.FaceValue) {
+			faceValues = append(faceValues, p.FaceValue)
+		}
+	}
+	sort.Float64s(faceValues)
+	return fulu.GetMobileInfoResult{
+		SP:        spNames[sp],
+		SpType:    string(sp),
+		FaceValue: faceValues,
+		Province:  "模拟省",
+		City:      "模拟市",
+		CityCode:  "000000",
+	}, nil
+}
+
+func (g *Gateway) getMobileMaintainStatus(bizContent []byte) (interface{}, error) {
+	var params fulu.GetMobileMaintainStatusReqParams
+	if err := decodeBizContent(bizContent, &params); err != nil {
+		return nil, err
+	}
+	sp, err := mobileSp(params.Mobile)
+	if err != nil {
+		return nil, err
+	}
+	return fulu.GetMobileMaintainStatusResult{
+		Province:           "模拟省",
+		City:               "模拟市",
+		Sp:                 spNames[sp],
+		SpType:             string(sp),
+		MaintainState:      string(fulu.MaintainStateOK),
+		CurrentSuccessRate: 1,
+	}, nil
+}
+
+// placeOrder 校验商品及余额后扣款并创建订单，调用方需持有锁
+func (g *Gateway) placeOrder(o *order, customerPrice float64) error {
+	if g.accountDisabled {
+		return NewError(fulu.CodeAccountDisabled, "")
+	}
+	if o.CustomerOrderNO == "" {
+		return NewError(fulu.CodeInvalidBizContent, "外部订单号不能为空")
+	}
+	if o.BuyNum <= 0 {
+		o.BuyNum = 1
+	}
+	if _, ok := g.orders[o.CustomerOrderNO]; ok {
+		return NewError(fulu.CodeDuplicateOrder, "")
+	}
+	product, ok := g.products[o.ProductID]
+	if !ok {
+		return NewError(fulu.CodeProductNotFound, "")
+	}
+	switch fulu.SaleStatus(product.SalesStatus) {
+	case fulu.SaleStatusInvalid:
+		return NewError(fulu.CodeProductOffline, "")
+	case fulu.SaleStatusMaintain, fulu.SaleStatusStockMaintain:
+		return NewError(fulu.CodeProductMaintain, "")
+	}
+	if fulu.StockStatus(product.StockStatus) == fulu.StockStatusOut {
+		return NewError(fulu.CodeProductOutOfStock, "")
+	}
+	price := math.Round(product.PurchasePrice*float64(o.BuyNum)*10000) / 10000
+	if customerPrice > 0 && customerPrice < price {
+		return NewError(fulu.CodePriceMismatch, "")
+	}
+	if price > g.balance {
+		return NewError(fulu.CodeInsufficientBalance, "")
+	}
+
+	g.balance = math.Round((g.balance-price)*10000) / 10000
+	now := time.Now()
+	o.OrderID = g.nextOrderID()
+	o.ProductName = product.ProductName
+	o.OrderPrice = price
+	o.CreateTime = now.Format(fulu.TimestampFormat)
+	o.createdAt = now
+	g.orders[o.CustomerOrderNO] = o
+	g.startLifecycle(o, now)
+	return nil
+}
+
+func (g *Gateway) createDirectOrder(bizContent []byte) (interface{}, error) {
+	var params fulu.CreateDirectOrderBizContent
+	if err := decodeBizContent(bizContent, &params); err != nil {
+		return nil, err
+	}
+	if params.ChargeAccount == "" {
+		return nil, NewError(fulu.CodeChargeAccountInvalid, "")
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var o = &order{
+		Order: fulu.Order{
+			CustomerOrderNO: params.CustomerOrder,
+			ProductID:       params.ProductID,
+			ChargeAccount:   params.ChargeAccount,
+			BuyNum:          params.BuyNum,
+			OrderType:       orderTypeDirect,
+			Area:            params.ChargeGameRegion,
+			Server:          params.ChargeGameName,
+			Type:            params.ChargeType,
+		},
+		ExternalBizID: params.ExternalBizId,
+	}
+	if err := g.placeOrder(o, params.CustomerPrice); err != nil {
+		return nil, err
+	}
+	return fulu.DirectOrderResult{
+		OrderID:              o.OrderID,
+		CustomerOrderNO:      o.CustomerOrderNO,
+		ProductID:            o.ProductID,
+		ProductName:          o.ProductName,
+		ChargeAccount:        o.ChargeAccount,
+		BuyNum:               o.BuyNum,
+		OrderType:            o.OrderType,
+		OrderPrice:           o.OrderPrice,
+		PrderState:           o.OrderState,
+		CreateTime:           o.CreateTime,
+		FinishTime:           o.FinishTime,
+		Area:                 o.Area,
+		Server:               o.Server,
+		Type:                 o.Type,
+		OperatorSerialNumber: o.OperatorSerialNumber,
+	}, nil
+}
+
+func (g *Gateway) createCardOrder(bizContent []byte) (interface{}, error) {
+	var params fulu.CreateCardOrderBizContent
+	if err := decodeBizContent(bizContent, &params); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var o = &order{
+		Order: fulu.Order{
+			CustomerOrderNO: params.CustomerOrderNO,
+			ProductID:       params.ProductID,
+			BuyNum:          params.BuyNum,
+			OrderType:       orderTypeCard,
+		},
+		ExternalBizID: params.ExternalBizID,
+	}
+	if err := g.placeOrder(o, params.CustomerPrice); err != nil {
+		return nil, err
+	}
+	return fulu.CardOrderResult{
+		OrderID:              o.OrderID,
+		CustomerOrderNO:      o.CustomerOrderNO,
+		ProductID:            o.ProductID,
+		ProductName:          o.ProductName,
+		BuyNum:               o.BuyNum,
+		OrderType:            o.OrderType,
+		OrderPrice:           o.OrderPrice,
+		OrderState:           o.OrderState,
+		CreateTime:           o.CreateTime,
+		FinishTime:           o.FinishTime,
+		OperatorSerialNumber: o.OperatorSerialNumber,
+	}, nil
+}
+
+func (g *Gateway) createMobileOrder(bizContent []byte) (interface{}, error) {
+	var params fulu.CreateMobileOrderBizContent
+	if err := decodeBizContent(bizContent, &params); err != nil {
+		return nil, err
+	}
+	if _, err := mobileSp(params.ChargePhone); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var productID int64
+	for _, p := range g.products {
+		if p.ProductType == ProductTypeMobile && p.FaceValue == params.ChargeValue {
+			productID = p.ProductID
+			break
+		}
+	}
+	var o = &order{
+		Order: fulu.Order{
+			CustomerOrderNO: params.CustomerOrderNO,
+			ProductID:       productID,
+			ChargeAccount:   params.ChargePhone,
+			BuyNum:          1,
+			OrderType:       orderTypeMobile,
+		},
+		ExternalBizID: params.ExternalBizID,
+	}
+	if err := g.placeOrder(o, params.CustomerPrice); err != nil {
+		return nil, err
+	}
+	return fulu.MobileOrderResult{
+		OrderID:              o.OrderID,
+		CustomerOrderNO:      o.CustomerOrderNO,
+		ProductID:            o.ProductID,
+		ProductName:          o.ProductName,
+		ChargeAccount:        o.ChargeAccount,
+		BuyNum:               o.BuyNum,
+		OrderPrice:           o.OrderPrice,
+		OrderType:            o.OrderType,
+		OrderState:           o.OrderState,
+		CreateTime:           o.CreateTime,
+		FinishTime:           o.FinishTime,
+		OperatorSerialNumber: o.OperatorSerialNumber,
+	}, nil
+}
+
+type customerOrderParams struct {
+	CustomerOrderNO string `json:"customer_order_no"`
+}
+
+func (g *Gateway) queryOrder(bizContent []byte) (interface{}, error) {
+	var params customerOrderParams
+	if err := decodeBizContent(bizContent, &params); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	o, ok := g.orders[params.CustomerOrderNO]
+	if !ok {
+		return nil, NewError(fulu.CodeOrderNotFound, "")
+	}
+	g.pollOrder(o, time.Now())
+	return o.Order, nil
+}
+
+func (g *Gateway) queryOrderExtend(bizContent []byte) (interface{}, error) {
+	var params customerOrderParams
+	if err := decodeBizContent(bizContent, &params); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	o, ok := g.orders[params.CustomerOrderNO]
+	if !ok {
+		return nil, NewError(fulu.CodeOrderNotFound, "")
+	}
+	content, err := jsoniter.MarshalToString(fulu.OrderExtendContent{
+		RechargeDescription: o.RechargeDescription,
+		ExternalBizID:       o.ExternalBizID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fulu.OrderExtendTemp{
+		OrderID:            o.OrderID,
+		CustomerOrderNO:    o.CustomerOrderNO,
+		OrderExtendContent: content,
+	}, nil
+}
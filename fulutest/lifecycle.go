@@ -0,0 +1,263 @@
+package fulutest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	jsoniter "github.com/json-iterator/go"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Lifecycle 订单生命周期脚本，未设置时订单在下单后立即成功
+type Lifecycle struct {
+	// State 最终状态，默认success；为processing或untreated时订单一直停留在该状态
+	State fulu.OrderState
+	// Delay 下单后多久到达最终状态
+	Delay time.Duration
+	// Polls 到达最终状态前QueryOrder返回处理中的次数，与Delay同时设置时需都满足
+	Polls int
+	// Cards 成功时返回的卡密，为空时卡密订单按购买数量生成
+	Cards []fulu.CardItem
+	// OperatorSerialNumber 成功时的运营商流水号，为空时自动生成
+	OperatorSerialNumber string
+	// RechargeDescription 充值描述，为空时按最终状态生成
+	RechargeDescription string
+	// NotifyURL 到达最终状态后推送订单结果的地址，为空时使用网关默认地址
+	NotifyURL string
+}
+
+// Notification 网关发出的订单结果推送
+type Notification struct {
+	URL          string                 `json:"url"`
+	Notification fulu.OrderNotification `json:"notification"`
+	StatusCode   int                    `json:"status_code"`
+	Ack          string                 `json:"ack"`
+	Error        string                 `json:"error,omitempty"`
+	Time         time.Time              `json:"time"`
+}
+
+// ErrOrderFinished 订单已到达最终状态
+var ErrOrderFinished = errors.New("fulutest: order already finished")
+
+// WithNotifyURL 设置订单结果推送的默认地址
+func WithNotifyURL(url string) Option {
+	return func(g *Gateway) {
+		g.notifyURL = url
+	}
+}
+
+// WithNotifyClient 设置推送订单结果使用的http.Client
+func WithNotifyClient(client *http.Client) Option {
+	return func(g *Gateway) {
+		g.notifyClient = client
+	}
+}
+
+// SetNotifyURL 修改订单结果推送的默认地址，为空时不推送
+func (g *Gateway) SetNotifyURL(url string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.notifyURL = url
+}
+
+// SetProductLifecycle 设置商品下所有新订单的生命周期
+func (g *Gateway) SetProductLifecycle(productID int64, lifecycle Lifecycle) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.productLifecycles[productID] = lifecycle
+}
+
+// SetOrderLifecycle 设置指定外部订单号的生命周期，优先于商品配置，需在下单前设置
+func (g *Gateway) SetOrderLifecycle(customerOrderNO string, lifecycle Lifecycle) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.orderLifecycles[customerOrderNO] = lifecycle
+}
+
+// FinishOrder 立即将处理中的订单置为最终状态
+func (g *Gateway) FinishOrder(customerOrderNO string, state fulu.OrderState) error {
+	if !fulu.IsFinalOrderState(state) {
+		return fmt.Errorf("fulutest: %s is not a final order state", state)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	o, ok := g.orders[customerOrderNO]
+	if !ok {
+		return NewError(fulu.CodeOrderNotFound, "")
+	}
+	if fulu.IsFinalOrderState(fulu.OrderState(o.OrderState)) {
+		return ErrOrderFinished
+	}
+	g.finishOrder(o, state, time.Now())
+	return nil
+}
+
+// Notifications 已发出的订单结果推送
+func (g *Gateway) Notifications() []Notification {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]Notification(nil), g.notifications...)
+}
+
+// Close 停止未到期的生命周期定时器并等待进行中的推送完成
+func (g *Gateway) Close() {
+	g.mu.Lock()
+	g.closed = true
+	for _, timer := range g.timers {
+		timer.Stop()
+	}
+	g.timers = nil
+	g.mu.Unlock()
+	g.notifying.Wait()
+}
+
+// lifecycle 订单生命周期，调用方需持有锁
+func (g *Gateway) lifecycle(o *order) Lifecycle {
+	lifecycle, ok := g.orderLifecycles[o.CustomerOrderNO]
+	if !ok {
+		lifecycle = g.productLifecycles[o.ProductID]
+	}
+	if lifecycle.State == "" {
+		lifecycle.State = fulu.OrderStateSuccess
+	}
+	return lifecycle
+}
+
+// startLifecycle 按生命周期设置新订单的初始状态，调用方需持有锁
+func (g *Gateway) startLifecycle(o *order, now time.Time) {
+	lifecycle := g.lifecycle(o)
+	o.lifecycle = lifecycle
+	if !fulu.IsFinalOrderState(lifecycle.State) {
+		o.OrderState = string(lifecycle.State)
+		return
+	}
+	if lifecycle.Delay <= 0 && lifecycle.Polls <= 0 {
+		g.finishOrder(o, lifecycle.State, now)
+		return
+	}
+	o.OrderState = fulu.OrderStateProcessing
+	if lifecycle.Delay > 0 && !g.closed {
+		g.timers = append(g.timers, time.AfterFunc(lifecycle.Delay, func() {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			g.advanceOrder(o, time.Now())
+		}))
+	}
+}
+
+// pollOrder QueryOrder时推进订单状态，调用方需持有锁
+func (g *Gateway) pollOrder(o *order, now time.Time) {
+	if g.advanceOrder(o, now) {
+		return
+	}
+	o.polls++
+}
+
+// advanceOrder 满足生命周期条件时将订单置为最终状态，调用方需持有锁
+func (g *Gateway) advanceOrder(o *order, now time.Time) bool {
+	if o.OrderState != fulu.OrderStateProcessing || !fulu.IsFinalOrderState(o.lifecycle.State) {
+		return false
+	}
+	if now.Sub(o.createdAt) < o.lifecycle.Delay || o.polls < o.lifecycle.Polls {
+		return false
+	}
+	g.finishOrder(o, o.lifecycle.State, now)
+	return true
+}
+
+// finishOrder 订单到达最终状态，成功时生成卡密，失败时退款，调用方需持有锁
+func (g *Gateway) finishOrder(o *order, state fulu.OrderState, now time.Time) {
+	o.OrderState = string(state)
+	o.FinishTime = now.Format(fulu.TimestampFormat)
+	switch state {
+	case fulu.OrderStateSuccess:
+		o.OperatorSerialNumber = o.lifecycle.OperatorSerialNumber
+		if o.OperatorSerialNumber == "" {
+			o.OperatorSerialNumber = "OSN" + o.OrderID
+		}
+		o.Cards = append([]fulu.CardItem(nil), o.lifecycle.Cards...)
+		if o.OrderType == orderTypeCard && len(o.Cards) == 0 {
+			for i := 0; i < o.BuyNum; i++ {
+				o.Cards = append(o.Cards, fulu.CardItem{
+					CardType:     1,
+					CardNumber:   fmt.Sprintf("FT%s%02d", o.OrderID, i+1),
+					CardPwd:      fmt.Sprintf("%s%02d", strings.Repeat("8", 8), i+1),
+					CardDeadline: now.AddDate(1, 0, 0).Format(fulu.TimestampFormat),
+				})
+			}
+		}
+		o.RechargeDescription = "充值成功"
+	case fulu.OrderStateFailed:
+		g.balance = math.Round((g.balance+o.OrderPrice)*10000) / 10000
+		o.RechargeDescription = "充值失败"
+	}
+	if o.lifecycle.RechargeDescription != "" {
+		o.RechargeDescription = o.lifecycle.RechargeDescription
+	}
+
+	var url = o.lifecycle.NotifyURL
+	if url == "" {
+		url = g.notifyURL
+	}
+	if url != "" && !g.closed {
+		g.notifying.Add(1)
+		go g.notify(url, g.orderNotification(o))
+	}
+}
+
+func (g *Gateway) orderNotification(o *order) fulu.OrderNotification {
+	return fulu.OrderNotification{
+		OrderID:              o.OrderID,
+		CustomerOrderNO:      o.CustomerOrderNO,
+		OrderStatus:          o.OrderState,
+		RechargeDescription:  o.RechargeDescription,
+		ProductID:            o.ProductID,
+		Price:                o.OrderPrice,
+		BuyNum:               o.BuyNum,
+		ChargeFinishTime:     o.FinishTime,
+		OperatorSerialNumber: o.OperatorSerialNumber,
+		Cards:                append([]fulu.CardItem(nil), o.Cards...),
+	}
+}
+
+// notify 推送签名后的订单结果并记录应答
+func (g *Gateway) notify(url string, notification fulu.OrderNotification) {
+	defer g.notifying.Done()
+	var record = Notification{URL: url, Time: time.Now()}
+	err := func() error {
+		payload, err := fulu.SignPayload(notification)
+		if err != nil {
+			return err
+		}
+		notification.Sign, err = g.signer.Sign(payload)
+		if err != nil {
+			return err
+		}
+		body, err := jsoniter.Marshal(notification)
+		if err != nil {
+			return err
+		}
+		resp, err := g.notifyClient.Post(url, "application/json; charset=utf-8", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		ack, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		record.StatusCode = resp.StatusCode
+		record.Ack = string(ack)
+		return nil
+	}()
+	if err != nil {
+		record.Error = err.Error()
+	}
+	record.Notification = notification
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.notifications = append(g.notifications, record)
+}
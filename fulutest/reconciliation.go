@@ -0,0 +1,102 @@
+package fulutest
+
+import (
+	"encoding/csv"
+	"fmt"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reconciliationPath 对账单下载地址前缀
+const reconciliationPath = "/fulutest/reconciliation/"
+
+type reconciliationTask struct {
+	fulu.ReconciliationTask
+	start time.Time
+	end   time.Time
+}
+
+func (g *Gateway) applyReconciliation(bizContent []byte) (interface{}, error) {
+	var params fulu.ApplyReconciliationParams
+	if err := decodeBizContent(bizContent, &params); err != nil {
+		return nil, err
+	}
+	start, err := time.ParseInLocation(fulu.TimestampFormat, params.StartTime, time.Local)
+	if err != nil {
+		return nil, NewError(fulu.CodeInvalidBizContent, "start_time格式错误")
+	}
+	end, err := time.ParseInLocation(fulu.TimestampFormat, params.EndTime, time.Local)
+	if err != nil || end.Before(start) {
+		return nil, NewError(fulu.CodeInvalidBizContent, "end_time格式错误")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.taskSeq++
+	var task = &reconciliationTask{
+		ReconciliationTask: fulu.ReconciliationTask{
+			TaskID: fmt.Sprintf("task%06d", g.taskSeq),
+			Status: fulu.ReconciliationStatusSuccess,
+		},
+		start: start,
+		end:   end,
+	}
+	task.DownloadURL = reconciliationPath + task.TaskID + ".csv"
+	g.tasks[task.TaskID] = task
+	var result = task.ReconciliationTask
+	return &result, nil
+}
+
+func (g *Gateway) getReconciliation(bizContent []byte) (interface{}, error) {
+	var params struct {
+		TaskID string `json:"task_id"`
+	}
+	if err := decodeBizContent(bizContent, &params); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	task, ok := g.tasks[params.TaskID]
+	if !ok {
+		return nil, NewError(fulu.CodeInvalidBizContent, "对账单任务不存在")
+	}
+	var result = task.ReconciliationTask
+	return &result, nil
+}
+
+// serveReconciliation 以csv格式输出任务时间范围内创建的订单
+func (g *Gateway) serveReconciliation(w http.ResponseWriter, r *http.Request) {
+	taskID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, reconciliationPath), ".csv")
+	g.mu.Lock()
+	task, ok := g.tasks[taskID]
+	g.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"order_id", "customer_order_no", "product_id", "product_name", "buy_num", "amount", "order_state", "create_time", "finish_time"})
+	for _, o := range g.Orders() {
+		createdAt, err := time.ParseInLocation(fulu.TimestampFormat, o.CreateTime, time.Local)
+		if err != nil || createdAt.Before(task.start) || createdAt.After(task.end) {
+			continue
+		}
+		_ = writer.Write([]string{
+			o.OrderID,
+			o.CustomerOrderNO,
+			strconv.FormatInt(o.ProductID, 10),
+			o.ProductName,
+			strconv.Itoa(o.BuyNum),
+			strconv.FormatFloat(o.OrderPrice, 'f', -1, 64),
+			o.OrderState,
+			o.CreateTime,
+			o.FinishTime,
+		})
+	}
+	writer.Flush()
+}
@@ -0,0 +1,39 @@
+package fulutest
+
+import (
+	fulu "github.com/t2krew/fulu-gosdk"
+	"net/http/httptest"
+)
+
+// Server 运行在本地端口的模拟网关
+type Server struct {
+	*Gateway
+	URL string
+	srv *httptest.Server
+}
+
+// NewServer 启动模拟网关，配置错误时panic
+func NewServer(opts ...Option) *Server {
+	gateway, err := NewGateway(opts...)
+	if err != nil {
+		panic("fulutest: " + err.Error())
+	}
+	srv := httptest.NewServer(gateway)
+	return &Server{Gateway: gateway, URL: srv.URL, srv: srv}
+}
+
+// Close 关闭模拟网关，等待进行中的订单结果推送完成
+func (s *Server) Close() {
+	s.srv.Close()
+	s.Gateway.Close()
+}
+
+// Config 访问模拟网关的客户端配置，默认开启响应签名校验
+func (s *Server) Config() fulu.Config {
+	return s.Gateway.Config(s.URL)
+}
+
+// NewClient 创建访问模拟网关的客户端
+func (s *Server) NewClient(opts ...fulu.Option) (*fulu.Client, error) {
+	return fulu.NewWithClient(s.Config(), s.srv.Client(), opts...)
+}
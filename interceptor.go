@@ -0,0 +1,75 @@
+package fulu_gosdk
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Call 一次接口调用
+type Call struct {
+	Method        Method            // 接口名称
+	BizContent    interface{}       // 业务参数
+	RawBizContent string            // 序列化后的业务参数
+	Params        *ReqParams        // 请求参数，发送前由最内层完成签名
+	Endpoint      string            // 网关地址
+	Headers       map[string]string // 额外的http请求头
+	Result        interface{}       // 业务结果的反序列化目标
+	Response      *RespData         // 福禄返回内容，请求失败时可能为nil
+	StatusCode    int               // http状态码
+	Attempt       int               // 当前请求次数，从1开始
+}
+
+// Invoker 执行接口调用
+type Invoker func(ctx context.Context, call *Call) error
+
+// Interceptor 接口调用拦截器，需调用next继续执行
+type Interceptor func(ctx context.Context, call *Call, next Invoker) error
+
+func chainInterceptors(interceptors []Interceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, call *Call) error {
+			return interceptor(ctx, call, next)
+		}
+	}
+	return invoker
+}
+
+// RetryInterceptor 按重试策略重试只读或幂等接口
+func RetryInterceptor(policy RetryPolicy) Interceptor {
+	return func(ctx context.Context, call *Call, next Invoker) error {
+		var attempts = policy.attempts(call.Method)
+		for attempt := 0; ; attempt++ {
+			call.Attempt = attempt + 1
+			err := next(ctx, call)
+			if err == nil || attempt+1 >= attempts || !policy.retryable(err) {
+				return err
+			}
+			if sleepContext(ctx, policy.Backoff.Delay(attempt)) != nil {
+				return err
+			}
+		}
+	}
+}
+
+// LoggingInterceptor 以结构化日志记录每次接口调用的耗时及结果
+func LoggingInterceptor(logger *slog.Logger) Interceptor {
+	return func(ctx context.Context, call *Call, next Invoker) error {
+		var start = time.Now()
+		err := next(ctx, call)
+		level, attrs := callLogAttrs(call, time.Since(start), err, nil)
+		logger.LogAttrs(ctx, level, "fulu call", attrs...)
+		return err
+	}
+}
+
+// MetricsInterceptor 上报每次接口调用的耗时及错误
+func MetricsInterceptor(observe func(method Method, elapsed time.Duration, err error)) Interceptor {
+	return func(ctx context.Context, call *Call, next Invoker) error {
+		var start = time.Now()
+		err := next(ctx, call)
+		observe(call.Method, time.Since(start), err)
+		return err
+	}
+}
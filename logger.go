@@ -0,0 +1,101 @@
+package fulu_gosdk
+
+import (
+	"context"
+	"fmt"
+	jsoniter "github.com/json-iterator/go"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// parseLogLevel 解析日志级别，级别为空且未开启Debug时不输出日志
+func parseLogLevel(level string, debug bool) (slog.Level, bool, error) {
+	switch strings.ToLower(level) {
+	case "":
+		if debug {
+			return slog.LevelDebug, true, nil
+		}
+		return slog.LevelInfo, false, nil
+	case "debug":
+		return slog.LevelDebug, true, nil
+	case "info":
+		return slog.LevelInfo, true, nil
+	case "warn", "warning":
+		return slog.LevelWarn, true, nil
+	case "error":
+		return slog.LevelError, true, nil
+	default:
+		return 0, false, fmt.Errorf("unsupported log level %q", level)
+	}
+}
+
+func (c *Client) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if c.logger == nil || level < c.logLevel {
+		return
+	}
+	c.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+func (c *Client) logInterceptor(ctx context.Context, call *Call, next Invoker) error {
+	var start = time.Now()
+	err := next(ctx, call)
+	level, attrs := callLogAttrs(call, time.Since(start), err, c.redactor)
+	c.log(ctx, level, "fulu call", attrs...)
+	return err
+}
+
+// callLogAttrs 单次调用的日志级别及字段
+func callLogAttrs(call *Call, latency time.Duration, err error, redactor *Redactor) (slog.Level, []slog.Attr) {
+	var attrs = []slog.Attr{
+		slog.String("method", string(call.Method)),
+		slog.Int("attempt", call.Attempt),
+		slog.Duration("latency", latency),
+		slog.Int("http_status", call.StatusCode),
+	}
+	if customerOrderNO := callCustomerOrderNO(call); customerOrderNO != "" {
+		attrs = append(attrs, slog.String("customer_order_no", customerOrderNO))
+	}
+	if call.Response != nil {
+		attrs = append(attrs, slog.Int("code", call.Response.Code))
+	}
+	if err != nil {
+		return slog.LevelWarn, append(attrs, slog.String("error", redactor.Redact(err.Error())))
+	}
+	return slog.LevelInfo, attrs
+}
+
+func callCustomerOrderNO(call *Call) string {
+	if call.RawBizContent == "" {
+		return ""
+	}
+	for _, key := range []string{"customer_order_no", "customer_order"} {
+		if v := jsoniter.Get([]byte(call.RawBizContent), key); v.ValueType() == jsoniter.StringValue {
+			return v.ToString()
+		}
+	}
+	return ""
+}
+
+// restyLogger 将resty调试日志脱敏后输出到结构化日志
+type restyLogger struct {
+	client *Client
+}
+
+func (l *restyLogger) Errorf(format string, v ...interface{}) {
+	l.output(slog.LevelError, format, v...)
+}
+
+func (l *restyLogger) Warnf(format string, v ...interface{}) {
+	l.output(slog.LevelWarn, format, v...)
+}
+
+func (l *restyLogger) Debugf(format string, v ...interface{}) {
+	l.output(slog.LevelDebug, format, v...)
+}
+
+func (l *restyLogger) output(level slog.Level, format string, v ...interface{}) {
+	l.client.log(context.Background(), level, "fulu http",
+		slog.String("dump", l.client.redactor.Redact(fmt.Sprintf(format, v...))),
+	)
+}
@@ -0,0 +1,117 @@
+package fulu_gosdk_test
+
+import (
+	"bytes"
+	"context"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"github.com/t2krew/fulu-gosdk/fulutest"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// syncBuffer 并发安全的日志缓冲
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestDebugLogRedactsSecrets(t *testing.T) {
+	const (
+		appAuthToken   = "auth-token-7f3a9c"
+		chargePassword = "charge-pwd-5e1b"
+		phone          = "13812345678"
+		qq             = "2718281828"
+		cardPwd        = "card-pwd-9d2c"
+	)
+	srv := fulutest.NewServer()
+	defer srv.Close()
+	srv.SeedProducts(
+		fulu.ProductInfo{ProductID: 1001, ProductName: "游戏直充", PurchasePrice: 10},
+		fulu.ProductInfo{ProductID: 1002, ProductName: "卡密", PurchasePrice: 10},
+		fulu.ProductInfo{ProductID: 1003, ProductName: "话费50元", ProductType: fulutest.ProductTypeMobile, FaceValue: 50, PurchasePrice: 49.5},
+	)
+	srv.SetBalance(1000)
+	srv.SeedQQNickname(qq, fulu.GetQQNicknameResult{Nickname: "tester"})
+	srv.SetOrderLifecycle("card-001", fulutest.Lifecycle{
+		Cards: []fulu.CardItem{{CardType: 1, CardNumber: "card-no-001", CardPwd: cardPwd}},
+	})
+
+	var (
+		logs = &syncBuffer{}
+		cfg  = srv.Config()
+	)
+	cfg.LogLevel = "debug"
+	cfg.AppAuthToken = appAuthToken
+	client, err := fulu.New(cfg, fulu.WithLogger(slog.New(slog.NewTextHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err = client.CreateDirectOrder(ctx, fulu.CreateDirectOrderBizContent{
+		ProductID:      1001,
+		CustomerOrder:  "direct-001",
+		ChargeAccount:  "player-001",
+		BuyNum:         1,
+		ChargePassword: chargePassword,
+		ContactQQ:      qq,
+		ContactTel:     phone,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.CreateMobileOrder(ctx, fulu.CreateMobileOrderBizContent{
+		ChargePhone:     phone,
+		ChargeValue:     50,
+		CustomerOrderNO: "mobile-001",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetQQNickname(ctx, qq); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.CreateCardOrder(ctx, fulu.CreateCardOrderBizContent{
+		ProductID:       1002,
+		BuyNum:          1,
+		CustomerOrderNO: "card-001",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	order, err := client.QueryOrder(ctx, "card-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(order.Cards) != 1 || order.Cards[0].CardPwd != cardPwd {
+		t.Fatalf("QueryOrder() cards = %+v, want card password returned to caller", order.Cards)
+	}
+
+	output := logs.String()
+	if !strings.Contains(output, "fulu.order.card.add") {
+		t.Fatalf("debug log missing request dump:\n%s", output)
+	}
+	for name, secret := range map[string]string{
+		"app_secret":      cfg.AppSecret,
+		"app_auth_token":  appAuthToken,
+		"charge_password": chargePassword,
+		"phone":           phone,
+		"qq":              qq,
+		"card_pwd":        cardPwd,
+	} {
+		if strings.Contains(output, secret) {
+			t.Errorf("debug log contains %s %q", name, secret)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package fulu_gosdk
+
+import (
+	"sync"
+	"time"
+)
+
+// MethodInfo 接口元数据
+type MethodInfo struct {
+	ReadOnly   bool          // 只读接口，不改变福禄侧数据，失败后可安全重试
+	Idempotent bool          // 幂等接口，失败后可安全重试
+	Timeout    time.Duration // 默认超时时间，ctx未设置超时时生效
+}
+
+const (
+	defaultReadTimeout  = 10 * time.Second
+	defaultWriteTimeout = 30 * time.Second
+)
+
+var (
+	methodsMu sync.RWMutex
+	methods   = map[Method]MethodInfo{
+		MethodGetProductList:          {ReadOnly: true, Idempotent: true, Timeout: defaultReadTimeout},
+		MethodGetProductInfo:          {ReadOnly: true, Idempotent: true, Timeout: defaultReadTimeout},
+		MethodGetProductTemplate:      {ReadOnly: true, Idempotent: true, Timeout: defaultReadTimeout},
+		MethodCheckProductStock:       {ReadOnly: true, Idempotent: true, Timeout: defaultReadTimeout},
+		MethodGetAccountInfo:          {ReadOnly: true, Idempotent: true, Timeout: defaultReadTimeout},
+		MethodGetQQNickname:           {ReadOnly: true, Idempotent: true, Timeout: defaultReadTimeout},
+		MethodGetMobileInfo:           {ReadOnly: true, Idempotent: true, Timeout: defaultReadTimeout},
+		MethodGetMobileMaintainStatus: {ReadOnly: true, Idempotent: true, Timeout: defaultReadTimeout},
+		MethodCreateDirectOrder:       {Timeout: defaultWriteTimeout},
+		MethodCreateCardOrder:         {Timeout: defaultWriteTimeout},
+		MethodCreateMobileOrder:       {Timeout: defaultWriteTimeout},
+		MethodQueryOrder:              {ReadOnly: true, Idempotent: true, Timeout: defaultReadTimeout},
+		MethodQueryOrderExtend:        {ReadOnly: true, Idempotent: true, Timeout: defaultReadTimeout},
+		MethodApplyReconciliation:     {Timeout: defaultWriteTimeout},
+		MethodGetReconciliation:       {ReadOnly: true, Idempotent: true, Timeout: defaultReadTimeout},
+	}
+)
+
+// Info 获取接口元数据，未注册的接口视为非幂等接口
+func (m Method) Info() MethodInfo {
+	methodsMu.RLock()
+	defer methodsMu.RUnlock()
+	return methods[m]
+}
+
+// RegisterMethod 注册或覆盖接口元数据
+func RegisterMethod(m Method, info MethodInfo) {
+	methodsMu.Lock()
+	defer methodsMu.Unlock()
+	methods[m] = info
+}
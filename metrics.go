@@ -0,0 +1,112 @@
+package fulu_gosdk
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Metrics 指标上报，实现需并发安全
+type Metrics interface {
+	// ObserveCall 接口调用耗时，code由ErrorCode得出：0为成功，-1为网络错误、非2xx响应、
+	// 签名不符、响应解析失败等没有可信福禄返回码的错误，其余为福禄返回码
+	ObserveCall(method Method, code int, latency time.Duration)
+	// OrderCreated 下单成功
+	OrderCreated(productID int64)
+	// OrderFinished 订单到达最终状态，由WaitForOrder及订单结果推送上报。
+	// 同一客户端按外部订单号去重，最近finishedOrderCacheSize个订单内只上报一次
+	OrderFinished(productID int64, state OrderState)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveCall(Method, int, time.Duration) {}
+func (nopMetrics) OrderCreated(int64)                     {}
+func (nopMetrics) OrderFinished(int64, OrderState)        {}
+
+// finishedOrderCacheSize 订单完成去重记录的外部订单号数量
+const finishedOrderCacheSize = 10000
+
+// finishedOrders 最近上报过完成的外部订单号，超出容量时淘汰最早的记录
+type finishedOrders struct {
+	mu   sync.Mutex
+	seen map[string]struct{}
+	ring []string
+	next int
+}
+
+func newFinishedOrders(size int) *finishedOrders {
+	return &finishedOrders{seen: make(map[string]struct{}, size), ring: make([]string, size)}
+}
+
+// add 记录外部订单号，已记录过时返回false
+func (f *finishedOrders) add(customerOrderNO string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.seen[customerOrderNO]; ok {
+		return false
+	}
+	if evicted := f.ring[f.next]; evicted != "" {
+		delete(f.seen, evicted)
+	}
+	f.ring[f.next] = customerOrderNO
+	f.next = (f.next + 1) % len(f.ring)
+	f.seen[customerOrderNO] = struct{}{}
+	return true
+}
+
+// orderFinished 上报订单完成，外部订单号为空时无法去重，总是上报
+func (c *Client) orderFinished(customerOrderNO string, productID int64, state OrderState) {
+	if customerOrderNO != "" && !c.finished.add(customerOrderNO) {
+		return
+	}
+	c.metrics.OrderFinished(productID, state)
+}
+
+// ExpvarMetrics 基于expvar的指标实现，通过/debug/vars查看
+type ExpvarMetrics struct {
+	calls          *expvar.Map
+	latency        *expvar.Map
+	codes          *expvar.Map
+	ordersCreated  *expvar.Map
+	ordersFinished *expvar.Map
+}
+
+// NewExpvarMetrics 初始化expvar指标，同名指标已存在时复用
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	root, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		root = expvar.NewMap(name)
+	}
+	return &ExpvarMetrics{
+		calls:          expvarSubMap(root, "calls"),
+		latency:        expvarSubMap(root, "latency_seconds"),
+		codes:          expvarSubMap(root, "codes"),
+		ordersCreated:  expvarSubMap(root, "orders_created"),
+		ordersFinished: expvarSubMap(root, "orders_finished"),
+	}
+}
+
+func expvarSubMap(root *expvar.Map, key string) *expvar.Map {
+	if m, ok := root.Get(key).(*expvar.Map); ok {
+		return m
+	}
+	m := new(expvar.Map).Init()
+	root.Set(key, m)
+	return m
+}
+
+func (m *ExpvarMetrics) ObserveCall(method Method, code int, latency time.Duration) {
+	m.calls.Add(string(method), 1)
+	m.latency.AddFloat(string(method), latency.Seconds())
+	m.codes.Add(string(method)+":"+strconv.Itoa(code), 1)
+}
+
+func (m *ExpvarMetrics) OrderCreated(productID int64) {
+	m.ordersCreated.Add(strconv.FormatInt(productID, 10), 1)
+}
+
+func (m *ExpvarMetrics) OrderFinished(productID int64, state OrderState) {
+	m.ordersFinished.Add(strconv.FormatInt(productID, 10)+":"+string(state), 1)
+}
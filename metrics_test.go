@@ -0,0 +1,121 @@
+package fulu_gosdk_test
+
+import (
+	"bytes"
+	"context"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"github.com/t2krew/fulu-gosdk/fulutest"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordMetrics 记录上报的返回码及完成的订单
+type recordMetrics struct {
+	mu       sync.Mutex
+	codes    []int
+	finished []int64
+}
+
+func (m *recordMetrics) ObserveCall(method fulu.Method, code int, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.codes = append(m.codes, code)
+}
+
+func (m *recordMetrics) OrderCreated(int64) {}
+
+func (m *recordMetrics) OrderFinished(productID int64, state fulu.OrderState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.finished = append(m.finished, productID)
+}
+
+func (m *recordMetrics) last() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.codes[len(m.codes)-1]
+}
+
+func TestMetricsObserveCallCode(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault fulutest.Fault
+		want  int
+	}{
+		{name: "success", want: fulu.CodeSuccess},
+		{name: "fulu code", fault: fulutest.Fault{Kind: fulutest.FaultCode, Code: fulu.CodeSystemBusy}, want: fulu.CodeSystemBusy},
+		{name: "http status", fault: fulutest.Fault{Kind: fulutest.FaultHTTPStatus}, want: -1},
+		{name: "conn reset", fault: fulutest.Fault{Kind: fulutest.FaultConnReset}, want: -1},
+		{name: "malformed json", fault: fulutest.Fault{Kind: fulutest.FaultMalformedJSON}, want: -1},
+		{name: "empty result", fault: fulutest.Fault{Kind: fulutest.FaultEmptyResult}, want: -1},
+		{name: "bad sign", fault: fulutest.Fault{Kind: fulutest.FaultBadSign}, want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := fulutest.NewServer()
+			defer srv.Close()
+			srv.ScriptFaults(fulu.MethodGetAccountInfo, tt.fault)
+
+			metrics := &recordMetrics{}
+			client, err := srv.NewClient(fulu.WithMetrics(metrics), fulu.WithRetryPolicy(fulu.NoRetry))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = client.GetAccountInfo(context.Background())
+			if (err != nil) != (tt.want != fulu.CodeSuccess) {
+				t.Fatalf("GetAccountInfo() error = %v", err)
+			}
+			if got := metrics.last(); got != tt.want {
+				t.Fatalf("ObserveCall() code = %d, want %d (err %v)", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestMetricsOrderFinishedDeduplicated(t *testing.T) {
+	srv := fulutest.NewServer(fulutest.WithCredentials(testAppKey, testAppSecret))
+	defer srv.Close()
+	srv.SetBalance(100)
+	srv.SeedProducts(
+		fulu.ProductInfo{ProductID: 1001, ProductName: "游戏直充", PurchasePrice: 10},
+		fulu.ProductInfo{ProductID: 1002, ProductName: "游戏直充", PurchasePrice: 10},
+	)
+
+	metrics := &recordMetrics{}
+	client, err := srv.NewClient(fulu.WithMetrics(metrics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.CreateDirectOrder(context.Background(), fulu.CreateDirectOrderBizContent{ProductID: 1001, CustomerOrder: "C001", ChargeAccount: "player", BuyNum: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		handler = client.OrderNotifyHandler(func(ctx context.Context, n *fulu.OrderNotification) error { return nil })
+		notify  = func(customerOrderNO string, productID int64) {
+			body := signBody(t, fulu.OrderNotification{CustomerOrderNO: customerOrderNO, ProductID: productID, OrderStatus: fulu.OrderStateSuccess}, nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/fulu/notify", bytes.NewReader(body)))
+			if w.Code != http.StatusOK {
+				t.Fatalf("notify %s response = %d %q", customerOrderNO, w.Code, w.Body.String())
+			}
+		}
+	)
+	for i := 0; i < 2; i++ {
+		if _, err = client.WaitForOrder(context.Background(), "C001", nil); err != nil {
+			t.Fatal(err)
+		}
+		notify("C001", 1001)
+		notify("C002", 1002)
+	}
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if want := []int64{1001, 1002}; !reflect.DeepEqual(metrics.finished, want) {
+		t.Fatalf("OrderFinished() product ids = %v, want %v", metrics.finished, want)
+	}
+}
@@ -1,5 +1,227 @@
 package fulu_gosdk
 
-func (c *Client) GetProductChangeInfo(data interface{}) {
+import (
+	"context"
+	"errors"
+	"fmt"
+	jsoniter "github.com/json-iterator/go"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
 
+// 推送应答内容，福禄收到非success应答时会重新推送
+const (
+	NotifyAckSuccess = "success"
+	NotifyAckFail    = "fail"
+)
+
+const maxNotifyBodySize = 1 << 20
+
+// OrderNotification 订单结果推送
+type OrderNotification struct {
+	OrderID              string     `json:"order_id"`
+	CustomerOrderNO      string     `json:"customer_order_no"`
+	OrderStatus          string     `json:"order_status"`
+	RechargeDescription  string     `json:"recharge_description"`
+	ProductID            int64      `json:"product_id"`
+	Price                float64    `json:"price"`
+	BuyNum               int        `json:"buy_num"`
+	ChargeFinishTime     string     `json:"charge_finish_time"`
+	OperatorSerialNumber string     `json:"operator_serial_number"`
+	Cards                []CardItem `json:"cards"`
+	Sign                 string     `json:"sign"`
+}
+
+// OrderNotifyFunc 订单结果推送处理函数，返回错误时福禄会重新推送
+type OrderNotifyFunc func(ctx context.Context, notification *OrderNotification) error
+
+// ParseOrderNotification 读取并校验订单结果推送
+func (c *Client) ParseOrderNotification(r *http.Request) (*OrderNotification, error) {
+	raw, err := readNotifyBody(r)
+	if err != nil {
+		return nil, err
+	}
+	err = VerifySign(c.signer, raw)
+	if err != nil {
+		return nil, err
+	}
+	var notification OrderNotification
+	err = jsoniter.Unmarshal(raw, &notification)
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// OrderNotifyHandler 订单结果推送回调处理器
+func (c *Client) OrderNotifyHandler(fn OrderNotifyFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeNotifyAck(w, http.StatusMethodNotAllowed, NotifyAckFail)
+			return
+		}
+		notification, err := c.ParseOrderNotification(r)
+		if err != nil {
+			writeNotifyAck(w, http.StatusBadRequest, NotifyAckFail)
+			return
+		}
+		if err = fn(r.Context(), notification); err != nil {
+			writeNotifyAck(w, http.StatusInternalServerError, NotifyAckFail)
+			return
+		}
+		if state := OrderState(notification.OrderStatus); IsFinalOrderState(state) {
+			c.orderFinished(notification.CustomerOrderNO, notification.ProductID, state)
+		}
+		writeNotifyAck(w, http.StatusOK, NotifyAckSuccess)
+	})
+}
+
+func readNotifyBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, errors.New("notify body is empty")
+	}
+	raw, err := io.ReadAll(io.LimitReader(r.Body, maxNotifyBodySize))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, errors.New("notify body is empty")
+	}
+	return raw, nil
+}
+
+func writeNotifyAck(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, body)
+}
+
+// ProductChangeType 商品变更类型
+type ProductChangeType string
+
+const (
+	ProductChangePrice  = ProductChangeType("price")  // 价格变更
+	ProductChangeStatus = ProductChangeType("status") // 销售状态变更
+	ProductChangeStock  = ProductChangeType("stock")  // 库存状态变更
+)
+
+// ProductChangeEvent 商品变更推送
+type ProductChangeEvent struct {
+	ProductID   int64             `json:"product_id"`
+	ProductName string            `json:"product_name"`
+	ChangeType  ProductChangeType `json:"change_type"`
+	OldValue    string            `json:"old_value"`
+	NewValue    string            `json:"new_value"`
+	ChangeTime  string            `json:"change_time"`
+	Sign        string            `json:"sign"`
+}
+
+// NewPrice 变更后的价格，仅价格变更有效
+func (e *ProductChangeEvent) NewPrice() (float64, error) {
+	if e.ChangeType != ProductChangePrice {
+		return 0, fmt.Errorf("product change type is %s, not price", e.ChangeType)
+	}
+	return strconv.ParseFloat(e.NewValue, 64)
+}
+
+// NewSaleStatus 变更后的销售状态，仅销售状态变更有效
+func (e *ProductChangeEvent) NewSaleStatus() SaleStatus {
+	if e.ChangeType != ProductChangeStatus {
+		return ""
+	}
+	return SaleStatus(e.NewValue)
+}
+
+// NewStockStatus 变更后的库存状态，仅库存状态变更有效
+func (e *ProductChangeEvent) NewStockStatus() StockStatus {
+	if e.ChangeType != ProductChangeStock {
+		return ""
+	}
+	return StockStatus(e.NewValue)
+}
+
+// GetProductChangeInfo 解析并校验商品变更推送内容
+func (c *Client) GetProductChangeInfo(raw []byte) (*ProductChangeEvent, error) {
+	err := VerifySign(c.signer, raw)
+	if err != nil {
+		return nil, err
+	}
+	var event ProductChangeEvent
+	err = jsoniter.Unmarshal(raw, &event)
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// ProductChangeListener 商品变更监听函数，返回错误时福禄会重新推送
+type ProductChangeListener func(ctx context.Context, event *ProductChangeEvent) error
+
+// ProductChangeHandler 商品变更推送回调处理器，按变更类型分发给已注册的监听函数
+type ProductChangeHandler struct {
+	client    *Client
+	mu        sync.RWMutex
+	listeners map[ProductChangeType][]ProductChangeListener
+	any       []ProductChangeListener
+}
+
+// NewProductChangeHandler 初始化商品变更推送回调处理器
+func (c *Client) NewProductChangeHandler() *ProductChangeHandler {
+	return &ProductChangeHandler{
+		client:    c,
+		listeners: make(map[ProductChangeType][]ProductChangeListener),
+	}
+}
+
+// On 注册监听函数，未指定变更类型时监听全部变更
+func (h *ProductChangeHandler) On(fn ProductChangeListener, changeTypes ...ProductChangeType) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(changeTypes) == 0 {
+		h.any = append(h.any, fn)
+		return
+	}
+	for _, changeType := range changeTypes {
+		h.listeners[changeType] = append(h.listeners[changeType], fn)
+	}
+}
+
+// Dispatch 将变更事件分发给监听函数，任一监听函数出错即返回
+func (h *ProductChangeHandler) Dispatch(ctx context.Context, event *ProductChangeEvent) error {
+	h.mu.RLock()
+	var listeners = make([]ProductChangeListener, 0, len(h.listeners[event.ChangeType])+len(h.any))
+	listeners = append(listeners, h.listeners[event.ChangeType]...)
+	listeners = append(listeners, h.any...)
+	h.mu.RUnlock()
+
+	for _, fn := range listeners {
+		if err := fn(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *ProductChangeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeNotifyAck(w, http.StatusMethodNotAllowed, NotifyAckFail)
+		return
+	}
+	raw, err := readNotifyBody(r)
+	if err != nil {
+		writeNotifyAck(w, http.StatusBadRequest, NotifyAckFail)
+		return
+	}
+	event, err := h.client.GetProductChangeInfo(raw)
+	if err != nil {
+		writeNotifyAck(w, http.StatusBadRequest, NotifyAckFail)
+		return
+	}
+	if err = h.Dispatch(r.Context(), event); err != nil {
+		writeNotifyAck(w, http.StatusInternalServerError, NotifyAckFail)
+		return
+	}
+	writeNotifyAck(w, http.StatusOK, NotifyAckSuccess)
 }
@@ -0,0 +1,230 @@
+package fulu_gosdk_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	jsoniter "github.com/json-iterator/go"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// signBody 按福禄规则为推送内容签名，tamper非nil时在签名后修改内容
+func signBody(t *testing.T, v interface{}, tamper func(map[string]interface{})) []byte {
+	t.Helper()
+	raw, err := jsoniter.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data map[string]interface{}
+	if err = jsoniter.Unmarshal(raw, &data); err != nil {
+		t.Fatal(err)
+	}
+	delete(data, "sign")
+	signer, err := fulu.NewSigner("md5", testAppSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := fulu.SignPayload(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data["sign"], err = signer.Sign(payload); err != nil {
+		t.Fatal(err)
+	}
+	if tamper != nil {
+		tamper(data)
+	}
+	raw, err = jsoniter.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func newNotifyClient(t *testing.T) *fulu.Client {
+	t.Helper()
+	client, err := fulu.New(fulu.Config{Endpoint: "http://127.0.0.1:0", AppKey: testAppKey, AppSecret: testAppSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestOrderNotifyHandler(t *testing.T) {
+	var notification = fulu.OrderNotification{
+		OrderID:         "F001",
+		CustomerOrderNO: "C001",
+		OrderStatus:     fulu.OrderStateSuccess,
+		ProductID:       1001,
+		Price:           9.8,
+		BuyNum:          1,
+	}
+	tests := []struct {
+		name       string
+		method     string
+		body       []byte
+		handlerErr error
+		wantStatus int
+		wantBody   string
+		wantCalled bool
+	}{
+		{
+			name:       "valid sign",
+			method:     http.MethodPost,
+			body:       signBody(t, notification, nil),
+			wantStatus: http.StatusOK,
+			wantBody:   fulu.NotifyAckSuccess,
+			wantCalled: true,
+		},
+		{
+			name:       "tampered sign",
+			method:     http.MethodPost,
+			body:       signBody(t, notification, func(data map[string]interface{}) { data["order_status"] = fulu.OrderStateFailed }),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   fulu.NotifyAckFail,
+		},
+		{
+			name:       "missing sign",
+			method:     http.MethodPost,
+			body:       signBody(t, notification, func(data map[string]interface{}) { delete(data, "sign") }),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   fulu.NotifyAckFail,
+		},
+		{
+			name:       "empty body",
+			method:     http.MethodPost,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   fulu.NotifyAckFail,
+		},
+		{
+			name:       "handler error",
+			method:     http.MethodPost,
+			body:       signBody(t, notification, nil),
+			handlerErr: errors.New("db unavailable"),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   fulu.NotifyAckFail,
+			wantCalled: true,
+		},
+		{
+			name:       "not post",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   fulu.NotifyAckFail,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called *fulu.OrderNotification
+			handler := newNotifyClient(t).OrderNotifyHandler(func(ctx context.Context, n *fulu.OrderNotification) error {
+				called = n
+				return tt.handlerErr
+			})
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, "/fulu/notify", bytes.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus || w.Body.String() != tt.wantBody {
+				t.Fatalf("response = %d %q, want %d %q", w.Code, w.Body.String(), tt.wantStatus, tt.wantBody)
+			}
+			if (called != nil) != tt.wantCalled {
+				t.Fatalf("handler called = %v, want %v", called != nil, tt.wantCalled)
+			}
+			if called != nil && (called.CustomerOrderNO != "C001" || called.OrderStatus != fulu.OrderStateSuccess) {
+				t.Fatalf("handler notification = %+v", called)
+			}
+		})
+	}
+}
+
+func TestProductChangeHandler(t *testing.T) {
+	var (
+		price  = fulu.ProductChangeEvent{ProductID: 1001, ChangeType: fulu.ProductChangePrice, OldValue: "9.8", NewValue: "9.9"}
+		status = fulu.ProductChangeEvent{ProductID: 1002, ChangeType: fulu.ProductChangeStatus, OldValue: "上架", NewValue: "下架"}
+	)
+	tests := []struct {
+		name        string
+		method      string
+		body        []byte
+		listenerErr error
+		wantStatus  int
+		wantBody    string
+		wantCalls   []string
+	}{
+		{
+			name:       "price change",
+			method:     http.MethodPost,
+			body:       signBody(t, price, nil),
+			wantStatus: http.StatusOK,
+			wantBody:   fulu.NotifyAckSuccess,
+			wantCalls:  []string{"price", "any"},
+		},
+		{
+			name:       "status change",
+			method:     http.MethodPost,
+			body:       signBody(t, status, nil),
+			wantStatus: http.StatusOK,
+			wantBody:   fulu.NotifyAckSuccess,
+			wantCalls:  []string{"status-stock", "any"},
+		},
+		{
+			name:       "tampered sign",
+			method:     http.MethodPost,
+			body:       signBody(t, price, func(data map[string]interface{}) { data["new_value"] = "0.01" }),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   fulu.NotifyAckFail,
+		},
+		{
+			name:       "missing sign",
+			method:     http.MethodPost,
+			body:       signBody(t, price, func(data map[string]interface{}) { delete(data, "sign") }),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   fulu.NotifyAckFail,
+		},
+		{
+			name:        "listener error",
+			method:      http.MethodPost,
+			body:        signBody(t, price, nil),
+			listenerErr: errors.New("cache unavailable"),
+			wantStatus:  http.StatusInternalServerError,
+			wantBody:    fulu.NotifyAckFail,
+			wantCalls:   []string{"price"},
+		},
+		{
+			name:       "not post",
+			method:     http.MethodPut,
+			body:       signBody(t, price, nil),
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   fulu.NotifyAckFail,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				calls   []string
+				handler = newNotifyClient(t).NewProductChangeHandler()
+				listen  = func(name string, err error) fulu.ProductChangeListener {
+					return func(ctx context.Context, event *fulu.ProductChangeEvent) error {
+						calls = append(calls, name)
+						return err
+					}
+				}
+			)
+			handler.On(listen("price", tt.listenerErr), fulu.ProductChangePrice)
+			handler.On(listen("status-stock", nil), fulu.ProductChangeStatus, fulu.ProductChangeStock)
+			handler.On(listen("any", nil))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, "/fulu/product", bytes.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus || w.Body.String() != tt.wantBody {
+				t.Fatalf("response = %d %q, want %d %q", w.Code, w.Body.String(), tt.wantStatus, tt.wantBody)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Fatalf("listeners called = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package fulu_gosdk
+
+import (
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+)
+
+// Option 客户端可选配置
+type Option func(*Client)
+
+// WithRetryPolicy 设置只读或幂等接口的重试策略，传入NoRetry可关闭重试
+func WithRetryPolicy(policy RetryPolicy) Option {
+	policy = policy.clone()
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithRateLimit 开启客户端限流，按接口及全局额度限制请求速率
+func WithRateLimit(cfg RateLimit) Option {
+	return func(c *Client) {
+		c.limiter = newRateLimiter(cfg)
+	}
+}
+
+// WithInterceptors 添加拦截器，按添加顺序由外向内执行，位于内置的重试及限流拦截器之外
+func WithInterceptors(interceptors ...Interceptor) Option {
+	interceptors = append([]Interceptor(nil), interceptors...)
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// WithSigner 使用自定义签名器，优先于Config.SignType
+func WithSigner(signer Signer) Option {
+	return func(c *Client) {
+		c.signer = signer
+	}
+}
+
+// WithRedactor 设置调试日志脱敏规则，AppSecret始终会被屏蔽
+func WithRedactor(redactor *Redactor) Option {
+	return func(c *Client) {
+		c.redactor = redactor
+	}
+}
+
+// WithLogger 设置结构化日志，日志级别由Config.LogLevel控制，默认为info
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithTracerProvider 开启OpenTelemetry链路追踪
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *Client) {
+		c.tracer = provider.Tracer(tracerName)
+	}
+}
+
+// WithMetrics 设置指标上报
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// WithCircuitBreaker 开启按接口熔断，网关连续失败时快速返回ErrCircuitOpen
+func WithCircuitBreaker(cfg CircuitBreakerConfig) Option {
+	return func(c *Client) {
+		c.breaker = newCircuitBreaker(cfg)
+	}
+}
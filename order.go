@@ -52,12 +52,13 @@ type DirectOrderResult struct {
 }
 
 // CreateDirectOrder 创建直充订单
-func (c *Client) CreateDirectOrder(ctx context.Context, params CreateDirectOrderBizContent) (*DirectOrderResult, error) {
+func (c *Client) CreateDirectOrder(ctx context.Context, params CreateDirectOrderBizContent, opts ...CallOption) (*DirectOrderResult, error) {
 	var result DirectOrderResult
-	err := c.Request(ctx, MethodCreateDirectOrder, params, &result)
+	err := c.Request(ctx, MethodCreateDirectOrder, params, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
+	c.metrics.OrderCreated(result.ProductID)
 	return &result, nil
 }
 
@@ -85,12 +86,13 @@ type CardOrderResult struct {
 }
 
 // CreateCardOrder 创建卡密订单
-func (c *Client) CreateCardOrder(ctx context.Context, params CreateCardOrderBizContent) (*CardOrderResult, error) {
+func (c *Client) CreateCardOrder(ctx context.Context, params CreateCardOrderBizContent, opts ...CallOption) (*CardOrderResult, error) {
 	var result CardOrderResult
-	err := c.Request(ctx, MethodCreateCardOrder, params, &result)
+	err := c.Request(ctx, MethodCreateCardOrder, params, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
+	c.metrics.OrderCreated(result.ProductID)
 	return &result, nil
 }
 
@@ -119,12 +121,13 @@ type MobileOrderResult struct {
 }
 
 // CreateMobileOrder 创建话费订单
-func (c *Client) CreateMobileOrder(ctx context.Context, params CreateMobileOrderBizContent) (*MobileOrderResult, error) {
+func (c *Client) CreateMobileOrder(ctx context.Context, params CreateMobileOrderBizContent, opts ...CallOption) (*MobileOrderResult, error) {
 	var result MobileOrderResult
-	err := c.Request(ctx, MethodCreateMobileOrder, params, &result)
+	err := c.Request(ctx, MethodCreateMobileOrder, params, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
+	c.metrics.OrderCreated(result.ProductID)
 	return &result, nil
 }
 
@@ -157,12 +160,12 @@ type Order struct {
 }
 
 // QueryOrder 订单查询
-func (c *Client) QueryOrder(ctx context.Context, customerOrderNO string) (*Order, error) {
+func (c *Client) QueryOrder(ctx context.Context, customerOrderNO string, opts ...CallOption) (*Order, error) {
 	var result Order
 	var params = map[string]string{
 		"customer_order_no": customerOrderNO,
 	}
-	err := c.Request(ctx, MethodQueryOrder, params, &result)
+	err := c.Request(ctx, MethodQueryOrder, params, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -188,19 +191,19 @@ type OrderExtend struct {
 }
 
 // QueryOrderExtend 订单扩展信息查询
-func (c *Client) QueryOrderExtend(ctx context.Context, customerOrderNO string) (*OrderExtend, error) {
+func (c *Client) QueryOrderExtend(ctx context.Context, customerOrderNO string, opts ...CallOption) (*OrderExtend, error) {
 	var result OrderExtendTemp
 	var params = map[string]string{
 		"customer_order_no": customerOrderNO,
 	}
-	err := c.Request(ctx, MethodQueryOrderExtend, params, &result)
+	err := c.Request(ctx, MethodQueryOrderExtend, params, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
 	var content OrderExtendContent
 	err = jsoniter.Unmarshal([]byte(result.OrderExtendContent), &content)
 	if err != nil {
-		return nil, err
+		return nil, &APIError{Method: MethodQueryOrderExtend, Err: err}
 	}
 	return &OrderExtend{
 		OrderID:            result.OrderID,
@@ -208,8 +211,3 @@ func (c *Client) QueryOrderExtend(ctx context.Context, customerOrderNO string) (
 		OrderExtendContent: &content,
 	}, nil
 }
-
-// GetReconciliation 对账单申请
-func (c *Client) GetReconciliation(ctx context.Context) {
-
-}
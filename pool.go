@@ -0,0 +1,256 @@
+package fulu_gosdk
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrTenantNotFound 商户不存在
+var ErrTenantNotFound = errors.New("fulu: tenant not found")
+
+// NewPoolTransport 多商户共享的http连接池
+func NewPoolTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          200,
+		MaxIdleConnsPerHost:   100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// ClientPool 多商户客户端池，所有客户端共享同一个http连接池
+type ClientPool struct {
+	transport http.RoundTripper
+	opts      []Option
+
+	mu      sync.RWMutex
+	clients map[string]*Client
+	stats   map[string]*tenantStats
+}
+
+// NewClientPool 初始化客户端池，transport为nil时使用NewPoolTransport，opts应用于池中所有客户端
+func NewClientPool(transport http.RoundTripper, opts ...Option) *ClientPool {
+	if transport == nil {
+		transport = NewPoolTransport()
+	}
+	return &ClientPool{
+		transport: transport,
+		opts:      append([]Option(nil), opts...),
+		clients:   make(map[string]*Client),
+		stats:     make(map[string]*tenantStats),
+	}
+}
+
+// Load 批量添加商户，任一配置校验失败时不添加任何商户
+func (p *ClientPool) Load(cfgs map[string]Config, opts ...Option) error {
+	var (
+		clients = make(map[string]*Client, len(cfgs))
+		stats   = make(map[string]*tenantStats, len(cfgs))
+	)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for tenant, cfg := range cfgs {
+		if _, ok := p.clients[tenant]; ok {
+			return fmt.Errorf("tenant %s already exists", tenant)
+		}
+		client, s, err := p.newClient(tenant, cfg, opts)
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", tenant, err)
+		}
+		clients[tenant] = client
+		stats[tenant] = s
+	}
+	for tenant, client := range clients {
+		p.clients[tenant] = client
+		p.stats[tenant] = stats[tenant]
+	}
+	return nil
+}
+
+// Add 添加商户
+func (p *ClientPool) Add(tenant string, cfg Config, opts ...Option) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.clients[tenant]; ok {
+		return fmt.Errorf("tenant %s already exists", tenant)
+	}
+	client, stats, err := p.newClient(tenant, cfg, opts)
+	if err != nil {
+		return err
+	}
+	p.clients[tenant] = client
+	p.stats[tenant] = stats
+	return nil
+}
+
+// Rotate 替换商户配置(如轮换密钥)，正在进行的调用继续使用旧客户端
+func (p *ClientPool) Rotate(tenant string, cfg Config, opts ...Option) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.clients[tenant]; !ok {
+		return ErrTenantNotFound
+	}
+	client, stats, err := p.newClient(tenant, cfg, opts)
+	if err != nil {
+		return err
+	}
+	p.clients[tenant] = client
+	p.stats[tenant] = stats
+	return nil
+}
+
+// Remove 移除商户
+func (p *ClientPool) Remove(tenant string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.clients[tenant]; !ok {
+		return false
+	}
+	delete(p.clients, tenant)
+	delete(p.stats, tenant)
+	return true
+}
+
+// Get 获取商户客户端
+func (p *ClientPool) Get(tenant string) (*Client, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	client, ok := p.clients[tenant]
+	if !ok {
+		return nil, ErrTenantNotFound
+	}
+	return client, nil
+}
+
+// Tenants 所有商户名称
+func (p *ClientPool) Tenants() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var tenants = make([]string, 0, len(p.clients))
+	for tenant := range p.clients {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	return tenants
+}
+
+// TenantStats 商户调用统计
+type TenantStats struct {
+	Calls         uint64        `json:"calls"`
+	Errors        uint64        `json:"errors"`
+	Latency       time.Duration `json:"latency"` // 累计耗时
+	OrdersCreated uint64        `json:"orders_created"`
+	OrdersSuccess uint64        `json:"orders_success"`
+	OrdersFailed  uint64        `json:"orders_failed"`
+	LastErrorCode int           `json:"last_error_code"` // 最近一次失败的ErrorCode，网络错误及非2xx响应等为-1
+	LastCallAt    time.Time     `json:"last_call_at"`
+}
+
+// Stats 各商户调用统计，轮换配置后统计继续累计
+func (p *ClientPool) Stats() map[string]TenantStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var stats = make(map[string]TenantStats, len(p.stats))
+	for tenant, s := range p.stats {
+		stats[tenant] = s.snapshot()
+	}
+	return stats
+}
+
+// newClient 复用已有的商户统计，配置校验由newclient完成
+func (p *ClientPool) newClient(tenant string, cfg Config, opts []Option) (*Client, *tenantStats, error) {
+	stats, ok := p.stats[tenant]
+	if !ok {
+		stats = &tenantStats{}
+	}
+	var options = make([]Option, 0, len(p.opts)+len(opts)+1)
+	options = append(options, p.opts...)
+	options = append(options, opts...)
+	options = append(options, WithInterceptors(MetricsInterceptor(stats.observeCall)), func(c *Client) {
+		c.metrics = &tenantMetrics{stats: stats, next: c.metrics}
+	})
+
+	// 每个商户独立的http.Client共享同一个Transport，避免resty修改共享的http.Client
+	client, err := newclient(cfg, resty.NewWithClient(&http.Client{Transport: p.transport}), options...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, stats, nil
+}
+
+type tenantStats struct {
+	calls         uint64
+	errors        uint64
+	latency       int64
+	ordersCreated uint64
+	ordersSuccess uint64
+	ordersFailed  uint64
+	lastErrorCode int64
+	lastCallAt    int64
+}
+
+func (s *tenantStats) snapshot() TenantStats {
+	var stats = TenantStats{
+		Calls:         atomic.LoadUint64(&s.calls),
+		Errors:        atomic.LoadUint64(&s.errors),
+		Latency:       time.Duration(atomic.LoadInt64(&s.latency)),
+		OrdersCreated: atomic.LoadUint64(&s.ordersCreated),
+		OrdersSuccess: atomic.LoadUint64(&s.ordersSuccess),
+		OrdersFailed:  atomic.LoadUint64(&s.ordersFailed),
+		LastErrorCode: int(atomic.LoadInt64(&s.lastErrorCode)),
+	}
+	if last := atomic.LoadInt64(&s.lastCallAt); last > 0 {
+		stats.LastCallAt = time.Unix(0, last)
+	}
+	return stats
+}
+
+// observeCall 按调用返回的错误统计，不依赖返回码判断成败
+func (s *tenantStats) observeCall(method Method, latency time.Duration, err error) {
+	atomic.AddUint64(&s.calls, 1)
+	atomic.AddInt64(&s.latency, int64(latency))
+	atomic.StoreInt64(&s.lastCallAt, time.Now().UnixNano())
+	if err != nil {
+		atomic.AddUint64(&s.errors, 1)
+		atomic.StoreInt64(&s.lastErrorCode, int64(ErrorCode(err)))
+	}
+}
+
+// tenantMetrics 汇总商户订单统计并转发给客户端原有的指标实现
+type tenantMetrics struct {
+	stats *tenantStats
+	next  Metrics
+}
+
+func (m *tenantMetrics) ObserveCall(method Method, code int, latency time.Duration) {
+	m.next.ObserveCall(method, code, latency)
+}
+
+func (m *tenantMetrics) OrderCreated(productID int64) {
+	atomic.AddUint64(&m.stats.ordersCreated, 1)
+	m.next.OrderCreated(productID)
+}
+
+func (m *tenantMetrics) OrderFinished(productID int64, state OrderState) {
+	switch state {
+	case OrderStateSuccess:
+		atomic.AddUint64(&m.stats.ordersSuccess, 1)
+	case OrderStateFailed:
+		atomic.AddUint64(&m.stats.ordersFailed, 1)
+	}
+	m.next.OrderFinished(productID, state)
+}
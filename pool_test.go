@@ -0,0 +1,49 @@
+package fulu_gosdk_test
+
+import (
+	"context"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"github.com/t2krew/fulu-gosdk/fulutest"
+	"testing"
+)
+
+func TestClientPoolStatsCountGatewayFailures(t *testing.T) {
+	srv := fulutest.NewServer()
+	defer srv.Close()
+	srv.ScriptFaults(fulu.MethodGetAccountInfo,
+		fulutest.Fault{Kind: fulutest.FaultHTTPStatus},
+		fulutest.Fault{Kind: fulutest.FaultBadSign},
+		fulutest.Fault{Kind: fulutest.FaultEmptyResult},
+		fulutest.Fault{Kind: fulutest.FaultNone},
+		fulutest.Fault{Kind: fulutest.FaultCode, Code: fulu.CodeSystemBusy},
+	)
+
+	pool := fulu.NewClientPool(nil, fulu.WithRetryPolicy(fulu.NoRetry))
+	if err := pool.Add("shop", srv.Config()); err != nil {
+		t.Fatal(err)
+	}
+	client, err := pool.Get("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		wantErrors    uint64
+		wantErrorCode int
+	}{
+		{name: "http status", wantErrors: 1, wantErrorCode: -1},
+		{name: "bad sign", wantErrors: 2, wantErrorCode: -1},
+		{name: "empty result", wantErrors: 3, wantErrorCode: -1},
+		{name: "success", wantErrors: 3, wantErrorCode: -1},
+		{name: "fulu code", wantErrors: 4, wantErrorCode: fulu.CodeSystemBusy},
+	}
+	for i, tt := range tests {
+		_, _ = client.GetAccountInfo(context.Background())
+		stats := pool.Stats()["shop"]
+		if stats.Calls != uint64(i+1) || stats.Errors != tt.wantErrors || stats.LastErrorCode != tt.wantErrorCode {
+			t.Fatalf("%s: stats calls=%d errors=%d last_error_code=%d, want %d, %d, %d",
+				tt.name, stats.Calls, stats.Errors, stats.LastErrorCode, i+1, tt.wantErrors, tt.wantErrorCode)
+		}
+	}
+}
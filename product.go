@@ -54,9 +54,9 @@ type ProductListItem struct {
 
 // GetProductList 获取商品列表
 // method: fulu.goods.list.get
-func (c *Client) GetProductList(ctx context.Context, params *GetProductListParams) ([]ProductListItem, error) {
+func (c *Client) GetProductList(ctx context.Context, params *GetProductListParams, opts ...CallOption) ([]ProductListItem, error) {
 	var result []ProductListItem
-	err := c.Request(ctx, MethodGetProductList, params, &result)
+	err := c.Request(ctx, MethodGetProductList, params, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +84,7 @@ type ProductInfo struct {
 	DetailType       int     `json:"detail_type"`
 }
 
-// GetProductInfo 获取商品信息
+// GetProductInfo 获取商品信息，单次调用配置通过ContextWithCallOptions传入
 // method: fulu.goods.info.get
 func (c *Client) GetProductInfo(ctx context.Context, productID string, format ...ProductDetailFormat) (*ProductInfo, error) {
 	var params = &GetProductInfoParams{
@@ -164,11 +164,11 @@ type productTemplateGameTemplateGameChargeRegionServer struct {
 }
 
 // GetProductTemplate 获取商品模板
-func (c *Client) GetProductTemplate(ctx context.Context, templateID string) (*ProductTemplate, error) {
+func (c *Client) GetProductTemplate(ctx context.Context, templateID string, opts ...CallOption) (*ProductTemplate, error) {
 	var params = &GetProductTemplateParams{TemplateID: templateID}
 	var result ProductTemplate
 
-	err := c.Request(ctx, MethodGetProductTemplate, params, &result)
+	err := c.Request(ctx, MethodGetProductTemplate, params, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -188,14 +188,14 @@ type CheckProductStockResult struct {
 }
 
 // CheckProductStock 校验商品库存
-func (c *Client) CheckProductStock(ctx context.Context, productID string, num int) (*CheckProductStockResult, error) {
+func (c *Client) CheckProductStock(ctx context.Context, productID string, num int, opts ...CallOption) (*CheckProductStockResult, error) {
 	var params = &CheckProductStockParams{
 		ProductID: productID,
 		BuyNum:    num,
 	}
 	var result CheckProductStockResult
 
-	err := c.Request(ctx, MethodCheckProductStock, params, &result)
+	err := c.Request(ctx, MethodCheckProductStock, params, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,191 @@
+package fulu_gosdk
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit 令牌桶配置
+type Limit struct {
+	Rate  float64 // 每秒令牌数
+	Burst int     // 桶容量
+}
+
+// RateLimit 客户端限流配置，Global为所有接口共享的额度
+type RateLimit struct {
+	Global    Limit
+	PerMethod map[Method]Limit
+	// 福禄返回限流错误后速率降低的比例及恢复时间
+	ThrottleFactor   float64
+	ThrottleCooldown time.Duration
+}
+
+// DefaultRateLimit 默认限流配置
+var DefaultRateLimit = RateLimit{
+	Global: Limit{Rate: 50, Burst: 50},
+	PerMethod: map[Method]Limit{
+		MethodGetProductList: {Rate: 1, Burst: 2},
+		MethodGetProductInfo: {Rate: 10, Burst: 10},
+	},
+	ThrottleFactor:   0.5,
+	ThrottleCooldown: 10 * time.Second,
+}
+
+type rateLimiter struct {
+	global    *tokenBucket
+	perMethod map[Method]*tokenBucket
+}
+
+func newRateLimiter(cfg RateLimit) *rateLimiter {
+	if cfg.ThrottleFactor <= 0 || cfg.ThrottleFactor >= 1 {
+		cfg.ThrottleFactor = DefaultRateLimit.ThrottleFactor
+	}
+	if cfg.ThrottleCooldown <= 0 {
+		cfg.ThrottleCooldown = DefaultRateLimit.ThrottleCooldown
+	}
+	var limiter = &rateLimiter{perMethod: make(map[Method]*tokenBucket, len(cfg.PerMethod))}
+	if cfg.Global.Rate > 0 {
+		limiter.global = newTokenBucket(cfg.Global, cfg.ThrottleFactor, cfg.ThrottleCooldown)
+	}
+	for method, limit := range cfg.PerMethod {
+		if limit.Rate > 0 {
+			limiter.perMethod[method] = newTokenBucket(limit, cfg.ThrottleFactor, cfg.ThrottleCooldown)
+		}
+	}
+	return limiter
+}
+
+// Wait 等待接口及全局额度，ctx结束时返回错误
+func (l *rateLimiter) Wait(ctx context.Context, method Method) error {
+	bucket, ok := l.perMethod[method]
+	if ok {
+		if err := bucket.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if l.global != nil {
+		if err := l.global.Wait(ctx); err != nil {
+			if ok {
+				// 请求未发出，归还已占用的接口额度
+				bucket.cancel()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *rateLimiter) interceptor(ctx context.Context, call *Call, next Invoker) error {
+	if err := l.Wait(ctx, call.Method); err != nil {
+		return err
+	}
+	err := next(ctx, call)
+	if errors.Is(err, ErrThrottled) {
+		l.Throttled(call.Method)
+	}
+	return err
+}
+
+// Throttled 福禄返回限流错误后降低该接口的速率
+func (l *rateLimiter) Throttled(method Method) {
+	if bucket, ok := l.perMethod[method]; ok {
+		bucket.Throttled()
+		return
+	}
+	if l.global != nil {
+		l.global.Throttled()
+	}
+}
+
+type tokenBucket struct {
+	mu       sync.Mutex
+	limit    Limit
+	factor   float64
+	cooldown time.Duration
+
+	tokens    float64
+	last      time.Time
+	penalty   float64 // 当前速率折扣
+	penaltyAt time.Time
+}
+
+func newTokenBucket(limit Limit, factor float64, cooldown time.Duration) *tokenBucket {
+	if limit.Burst <= 0 {
+		limit.Burst = int(math.Max(1, math.Ceil(limit.Rate)))
+	}
+	return &tokenBucket{
+		limit:    limit,
+		factor:   factor,
+		cooldown: cooldown,
+		tokens:   float64(limit.Burst),
+		last:     time.Now(),
+		penalty:  1,
+	}
+}
+
+// rate 当前速率，限流惩罚在cooldown内线性恢复
+func (b *tokenBucket) rate(now time.Time) float64 {
+	if b.penalty >= 1 {
+		return b.limit.Rate
+	}
+	elapsed := now.Sub(b.penaltyAt)
+	if elapsed >= b.cooldown {
+		b.penalty = 1
+		return b.limit.Rate
+	}
+	recovered := b.penalty + (1-b.penalty)*float64(elapsed)/float64(b.cooldown)
+	return b.limit.Rate * recovered
+}
+
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	rate := b.rate(now)
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / rate * float64(time.Second))
+}
+
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	delay := b.reserve(time.Now())
+	b.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		b.cancel()
+		return context.DeadlineExceeded
+	}
+	if err := sleepContext(ctx, delay); err != nil {
+		b.cancel()
+		return err
+	}
+	return nil
+}
+
+// cancel 归还未使用的令牌
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+1)
+	b.mu.Unlock()
+}
+
+func (b *tokenBucket) Throttled() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	rate := b.rate(now)
+	b.tokens = b.tokens + now.Sub(b.last).Seconds()*rate
+	b.last = now
+	b.penalty = math.Max(0.05, rate/b.limit.Rate*b.factor)
+	b.penaltyAt = now
+	if b.tokens > 0 {
+		b.tokens = 0
+	}
+}
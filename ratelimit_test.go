@@ -0,0 +1,32 @@
+package fulu_gosdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReturnsMethodTokenOnGlobalTimeout(t *testing.T) {
+	limiter := newRateLimiter(RateLimit{
+		Global:    Limit{Rate: 0.1, Burst: 1},
+		PerMethod: map[Method]Limit{MethodQueryOrder: {Rate: 0.1, Burst: 2}},
+	})
+	if err := limiter.Wait(context.Background(), MethodQueryOrder); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, MethodQueryOrder); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	bucket := limiter.perMethod[MethodQueryOrder]
+	bucket.mu.Lock()
+	tokens := bucket.tokens
+	bucket.mu.Unlock()
+	if tokens < 1 {
+		t.Fatalf("method bucket tokens = %.2f after global timeout, want the token returned", tokens)
+	}
+}
@@ -0,0 +1,169 @@
+package fulu_gosdk
+
+import (
+	"encoding/csv"
+	jsoniter "github.com/json-iterator/go"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// DefaultAmountTolerance 默认金额比对误差
+const DefaultAmountTolerance = 0.005
+
+// DiffKind 对账差异类型
+type DiffKind string
+
+const (
+	DiffMissingLocal   = DiffKind("missing_local")   // 福禄有订单，本地无订单
+	DiffMissingFulu    = DiffKind("missing_fulu")    // 本地有订单，福禄无订单
+	DiffAmountMismatch = DiffKind("amount_mismatch") // 金额不一致
+	DiffStateMismatch  = DiffKind("state_mismatch")  // 状态不一致
+	DiffDuplicateLocal = DiffKind("duplicate_local") // 本地订单号重复
+	DiffDuplicateFulu  = DiffKind("duplicate_fulu")  // 福禄对账单订单号重复，首条之外的每条记录分别上报
+)
+
+// ReconcileDiff 对账差异
+type ReconcileDiff struct {
+	Kind            DiffKind              `json:"kind"`
+	CustomerOrderNO string                `json:"customer_order_no"`
+	Fulu            *ReconciliationRecord `json:"fulu,omitempty"`
+	Local           *Order                `json:"local,omitempty"`
+}
+
+// ReconcileReport 对账结果
+type ReconcileReport struct {
+	FuluCount  int             `json:"fulu_count"`
+	LocalCount int             `json:"local_count"`
+	Matched    int             `json:"matched"`
+	Diffs      []ReconcileDiff `json:"diffs"`
+}
+
+// OrderSource 本地订单数据源，遍历结束时返回io.EOF
+type OrderSource interface {
+	Next() (*Order, error)
+}
+
+type sliceOrderSource struct {
+	orders []Order
+	pos    int
+}
+
+func (s *sliceOrderSource) Next() (*Order, error) {
+	if s.pos >= len(s.orders) {
+		return nil, io.EOF
+	}
+	order := &s.orders[s.pos]
+	s.pos++
+	return order, nil
+}
+
+// OrderSlice 以切片作为本地订单数据源
+func OrderSlice(orders []Order) OrderSource {
+	return &sliceOrderSource{orders: orders}
+}
+
+// Reconcile 按外部订单号比对福禄对账单与本地订单
+func Reconcile(records []ReconciliationRecord, local OrderSource, tolerance ...float64) (*ReconcileReport, error) {
+	var amountTolerance = DefaultAmountTolerance
+	if len(tolerance) > 0 {
+		amountTolerance = tolerance[0]
+	}
+
+	var (
+		report  = &ReconcileReport{FuluCount: len(records), Diffs: []ReconcileDiff{}}
+		pending = make(map[string]*ReconciliationRecord, len(records))
+		seen    = make(map[string]struct{})
+	)
+	for i := range records {
+		customerOrderNO := records[i].CustomerOrderNO
+		if _, ok := pending[customerOrderNO]; ok {
+			report.Diffs = append(report.Diffs, ReconcileDiff{Kind: DiffDuplicateFulu, CustomerOrderNO: customerOrderNO, Fulu: &records[i]})
+			continue
+		}
+		pending[customerOrderNO] = &records[i]
+	}
+
+	for {
+		order, err := local.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		report.LocalCount++
+
+		if _, ok := seen[order.CustomerOrderNO]; ok {
+			report.Diffs = append(report.Diffs, ReconcileDiff{Kind: DiffDuplicateLocal, CustomerOrderNO: order.CustomerOrderNO, Local: order})
+			continue
+		}
+		seen[order.CustomerOrderNO] = struct{}{}
+
+		record, ok := pending[order.CustomerOrderNO]
+		if !ok {
+			report.Diffs = append(report.Diffs, ReconcileDiff{Kind: DiffMissingFulu, CustomerOrderNO: order.CustomerOrderNO, Local: order})
+			continue
+		}
+		delete(pending, order.CustomerOrderNO)
+
+		var matched = true
+		if math.Abs(record.Amount-order.OrderPrice) > amountTolerance {
+			matched = false
+			report.Diffs = append(report.Diffs, ReconcileDiff{Kind: DiffAmountMismatch, CustomerOrderNO: order.CustomerOrderNO, Fulu: record, Local: order})
+		}
+		if record.OrderState != OrderState(order.OrderState) {
+			matched = false
+			report.Diffs = append(report.Diffs, ReconcileDiff{Kind: DiffStateMismatch, CustomerOrderNO: order.CustomerOrderNO, Fulu: record, Local: order})
+		}
+		if matched {
+			report.Matched++
+		}
+	}
+
+	var missing = make([]string, 0, len(pending))
+	for customerOrderNO := range pending {
+		missing = append(missing, customerOrderNO)
+	}
+	sort.Strings(missing)
+	for _, customerOrderNO := range missing {
+		report.Diffs = append(report.Diffs, ReconcileDiff{Kind: DiffMissingLocal, CustomerOrderNO: customerOrderNO, Fulu: pending[customerOrderNO]})
+	}
+	return report, nil
+}
+
+// WriteJSON 以json格式输出对账结果
+func (r *ReconcileReport) WriteJSON(w io.Writer) error {
+	return jsoniter.NewEncoder(w).Encode(r)
+}
+
+// WriteCSV 以csv格式输出对账差异
+func (r *ReconcileReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{
+		"kind", "customer_order_no", "fulu_order_id", "local_order_id",
+		"fulu_amount", "local_amount", "fulu_state", "local_state",
+	})
+	if err != nil {
+		return err
+	}
+	for _, diff := range r.Diffs {
+		var row = []string{string(diff.Kind), diff.CustomerOrderNO, "", "", "", "", "", ""}
+		if diff.Fulu != nil {
+			row[2] = diff.Fulu.OrderID
+			row[4] = strconv.FormatFloat(diff.Fulu.Amount, 'f', -1, 64)
+			row[6] = string(diff.Fulu.OrderState)
+		}
+		if diff.Local != nil {
+			row[3] = diff.Local.OrderID
+			row[5] = strconv.FormatFloat(diff.Local.OrderPrice, 'f', -1, 64)
+			row[7] = diff.Local.OrderState
+		}
+		if err = writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
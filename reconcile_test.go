@@ -0,0 +1,209 @@
+package fulu_gosdk_test
+
+import (
+	"bytes"
+	"errors"
+	jsoniter "github.com/json-iterator/go"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"reflect"
+	"testing"
+)
+
+type failingSource struct{}
+
+func (failingSource) Next() (*fulu.Order, error) {
+	return nil, errors.New("db unavailable")
+}
+
+type diffKey struct {
+	Kind            fulu.DiffKind
+	CustomerOrderNO string
+	FuluOrderID     string
+	LocalOrderID    string
+}
+
+func diffKeys(diffs []fulu.ReconcileDiff) []diffKey {
+	var keys = make([]diffKey, 0, len(diffs))
+	for _, diff := range diffs {
+		key := diffKey{Kind: diff.Kind, CustomerOrderNO: diff.CustomerOrderNO}
+		if diff.Fulu != nil {
+			key.FuluOrderID = diff.Fulu.OrderID
+		}
+		if diff.Local != nil {
+			key.LocalOrderID = diff.Local.OrderID
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name        string
+		records     []fulu.ReconciliationRecord
+		local       []fulu.Order
+		tolerance   []float64
+		wantMatched int
+		wantDiffs   []diffKey
+	}{
+		{
+			name:        "matched",
+			records:     []fulu.ReconciliationRecord{{OrderID: "F1", CustomerOrderNO: "C1", Amount: 9.8, OrderState: fulu.OrderStateSuccess}},
+			local:       []fulu.Order{{OrderID: "L1", CustomerOrderNO: "C1", OrderPrice: 9.801, OrderState: fulu.OrderStateSuccess}},
+			wantMatched: 1,
+			wantDiffs:   []diffKey{},
+		},
+		{
+			name: "missing on both sides",
+			records: []fulu.ReconciliationRecord{
+				{OrderID: "F2", CustomerOrderNO: "C2", Amount: 1, OrderState: fulu.OrderStateSuccess},
+				{OrderID: "F1", CustomerOrderNO: "C1", Amount: 1, OrderState: fulu.OrderStateSuccess},
+			},
+			local: []fulu.Order{{OrderID: "L3", CustomerOrderNO: "C3", OrderPrice: 1, OrderState: fulu.OrderStateSuccess}},
+			wantDiffs: []diffKey{
+				{Kind: fulu.DiffMissingFulu, CustomerOrderNO: "C3", LocalOrderID: "L3"},
+				{Kind: fulu.DiffMissingLocal, CustomerOrderNO: "C1", FuluOrderID: "F1"},
+				{Kind: fulu.DiffMissingLocal, CustomerOrderNO: "C2", FuluOrderID: "F2"},
+			},
+		},
+		{
+			name:    "amount and state mismatch",
+			records: []fulu.ReconciliationRecord{{OrderID: "F1", CustomerOrderNO: "C1", Amount: 9.8, OrderState: fulu.OrderStateFailed}},
+			local:   []fulu.Order{{OrderID: "L1", CustomerOrderNO: "C1", OrderPrice: 10, OrderState: fulu.OrderStateSuccess}},
+			wantDiffs: []diffKey{
+				{Kind: fulu.DiffAmountMismatch, CustomerOrderNO: "C1", FuluOrderID: "F1", LocalOrderID: "L1"},
+				{Kind: fulu.DiffStateMismatch, CustomerOrderNO: "C1", FuluOrderID: "F1", LocalOrderID: "L1"},
+			},
+		},
+		{
+			name:        "custom tolerance",
+			records:     []fulu.ReconciliationRecord{{OrderID: "F1", CustomerOrderNO: "C1", Amount: 9.8, OrderState: fulu.OrderStateSuccess}},
+			local:       []fulu.Order{{OrderID: "L1", CustomerOrderNO: "C1", OrderPrice: 9.85, OrderState: fulu.OrderStateSuccess}},
+			tolerance:   []float64{0.1},
+			wantMatched: 1,
+			wantDiffs:   []diffKey{},
+		},
+		{
+			name:    "duplicate local",
+			records: []fulu.ReconciliationRecord{{OrderID: "F1", CustomerOrderNO: "C1", Amount: 1, OrderState: fulu.OrderStateSuccess}},
+			local: []fulu.Order{
+				{OrderID: "L1", CustomerOrderNO: "C1", OrderPrice: 1, OrderState: fulu.OrderStateSuccess},
+				{OrderID: "L2", CustomerOrderNO: "C1", OrderPrice: 1, OrderState: fulu.OrderStateSuccess},
+			},
+			wantMatched: 1,
+			wantDiffs:   []diffKey{{Kind: fulu.DiffDuplicateLocal, CustomerOrderNO: "C1", LocalOrderID: "L2"}},
+		},
+		{
+			name: "duplicate fulu",
+			records: []fulu.ReconciliationRecord{
+				{OrderID: "F1", CustomerOrderNO: "C1", Amount: 1, OrderState: fulu.OrderStateSuccess},
+				{OrderID: "F2", CustomerOrderNO: "C1", Amount: 1, OrderState: fulu.OrderStateSuccess},
+				{OrderID: "F3", CustomerOrderNO: "C1", Amount: 1, OrderState: fulu.OrderStateSuccess},
+			},
+			local:       []fulu.Order{{OrderID: "L1", CustomerOrderNO: "C1", OrderPrice: 1, OrderState: fulu.OrderStateSuccess}},
+			wantMatched: 1,
+			wantDiffs: []diffKey{
+				{Kind: fulu.DiffDuplicateFulu, CustomerOrderNO: "C1", FuluOrderID: "F2"},
+				{Kind: fulu.DiffDuplicateFulu, CustomerOrderNO: "C1", FuluOrderID: "F3"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := fulu.Reconcile(tt.records, fulu.OrderSlice(tt.local), tt.tolerance...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.FuluCount != len(tt.records) || report.LocalCount != len(tt.local) || report.Matched != tt.wantMatched {
+				t.Fatalf("report counts = fulu %d, local %d, matched %d, want %d, %d, %d",
+					report.FuluCount, report.LocalCount, report.Matched, len(tt.records), len(tt.local), tt.wantMatched)
+			}
+			if got := diffKeys(report.Diffs); !reflect.DeepEqual(got, tt.wantDiffs) {
+				t.Fatalf("diffs = %+v, want %+v", got, tt.wantDiffs)
+			}
+		})
+	}
+}
+
+func TestReconcileSourceError(t *testing.T) {
+	if _, err := fulu.Reconcile(nil, failingSource{}); err == nil {
+		t.Fatal("Reconcile() error = nil, want source error")
+	}
+}
+
+func testReport() *fulu.ReconcileReport {
+	return &fulu.ReconcileReport{
+		FuluCount:  2,
+		LocalCount: 1,
+		Diffs: []fulu.ReconcileDiff{
+			{
+				Kind:            fulu.DiffAmountMismatch,
+				CustomerOrderNO: "C1",
+				Fulu:            &fulu.ReconciliationRecord{OrderID: "F1", CustomerOrderNO: "C1", Amount: 9.8, OrderState: fulu.OrderStateSuccess},
+				Local:           &fulu.Order{OrderID: "L1", CustomerOrderNO: "C1", OrderPrice: 10, OrderState: fulu.OrderStateSuccess},
+			},
+			{
+				Kind:            fulu.DiffDuplicateFulu,
+				CustomerOrderNO: "C1",
+				Fulu:            &fulu.ReconciliationRecord{OrderID: "F2", CustomerOrderNO: "C1", Amount: 9.8, OrderState: fulu.OrderStateFailed},
+			},
+		},
+	}
+}
+
+func TestReconcileReportWriteCSV(t *testing.T) {
+	tests := []struct {
+		name   string
+		report *fulu.ReconcileReport
+		want   string
+	}{
+		{
+			name:   "empty",
+			report: &fulu.ReconcileReport{},
+			want:   "kind,customer_order_no,fulu_order_id,local_order_id,fulu_amount,local_amount,fulu_state,local_state\n",
+		},
+		{
+			name:   "diffs",
+			report: testReport(),
+			want: "kind,customer_order_no,fulu_order_id,local_order_id,fulu_amount,local_amount,fulu_state,local_state\n" +
+				"amount_mismatch,C1,F1,L1,9.8,10,success,success\n" +
+				"duplicate_fulu,C1,F2,,9.8,,failed,\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.report.WriteCSV(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Fatalf("WriteCSV() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileReportWriteJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		report *fulu.ReconcileReport
+	}{
+		{name: "empty", report: &fulu.ReconcileReport{Diffs: []fulu.ReconcileDiff{}}},
+		{name: "diffs", report: testReport()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.report.WriteJSON(&buf); err != nil {
+				t.Fatal(err)
+			}
+			var got fulu.ReconcileReport
+			if err := jsoniter.NewDecoder(&buf).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(&got, tt.report) {
+				t.Fatalf("WriteJSON() round trip = %+v, want %+v", got, tt.report)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package fulu_gosdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReconciliationStatus 对账单生成状态
+type ReconciliationStatus string
+
+const (
+	ReconciliationStatusProcessing = ReconciliationStatus("processing") // 生成中
+	ReconciliationStatusSuccess    = ReconciliationStatus("success")    // 已生成
+	ReconciliationStatusFailed     = ReconciliationStatus("failed")     // 生成失败
+)
+
+// DefaultReconciliationPollInterval 默认对账单轮询间隔
+const DefaultReconciliationPollInterval = 5 * time.Second
+
+// ApplyReconciliationParams 对账单申请请求参数
+type ApplyReconciliationParams struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+// ReconciliationTask 对账单任务
+type ReconciliationTask struct {
+	TaskID      string               `json:"task_id"`
+	Status      ReconciliationStatus `json:"status"`
+	DownloadURL string               `json:"download_url"`
+	Message     string               `json:"message"`
+}
+
+// ReconciliationRecord 对账单明细
+type ReconciliationRecord struct {
+	OrderID         string     `json:"order_id"`
+	CustomerOrderNO string     `json:"customer_order_no"`
+	ProductID       int64      `json:"product_id"`
+	ProductName     string     `json:"product_name"`
+	BuyNum          int        `json:"buy_num"`
+	Amount          float64    `json:"amount"`
+	OrderState      OrderState `json:"order_state"`
+	CreateTime      time.Time  `json:"create_time"`
+	FinishTime      time.Time  `json:"finish_time"`
+}
+
+// ApplyReconciliation 对账单申请
+func (c *Client) ApplyReconciliation(ctx context.Context, start, end time.Time, opts ...CallOption) (*ReconciliationTask, error) {
+	var (
+		result ReconciliationTask
+		params = ApplyReconciliationParams{
+			StartTime: start.Format(TimestampFormat),
+			EndTime:   end.Format(TimestampFormat),
+		}
+	)
+	err := c.Request(ctx, MethodApplyReconciliation, params, &result, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// QueryReconciliation 对账单任务查询
+func (c *Client) QueryReconciliation(ctx context.Context, taskID string, opts ...CallOption) (*ReconciliationTask, error) {
+	var (
+		result ReconciliationTask
+		params = map[string]string{
+			"task_id": taskID,
+		}
+	)
+	err := c.Request(ctx, MethodGetReconciliation, params, &result, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DownloadReconciliation 下载并解析对账单文件
+func (c *Client) DownloadReconciliation(ctx context.Context, downloadURL string) ([]ReconciliationRecord, error) {
+	resp, err := c.httpCli.R().SetContext(ctx).SetDoNotParseResponse(true).Get(downloadURL)
+	if err != nil {
+		return nil, err
+	}
+	body := resp.RawBody()
+	defer body.Close()
+
+	if !resp.IsSuccess() {
+		return nil, &APIError{Method: MethodGetReconciliation, StatusCode: resp.StatusCode()}
+	}
+	return ParseReconciliation(body)
+}
+
+// ReconciliationOptions 对账单获取参数
+type ReconciliationOptions struct {
+	PollInterval time.Duration // 任务状态轮询间隔，0为DefaultReconciliationPollInterval
+	CallOptions  []CallOption  // 申请及查询任务时的调用配置
+}
+
+// GetReconciliation 申请对账单并等待生成完成后下载解析，opts为nil时使用默认参数
+func (c *Client) GetReconciliation(ctx context.Context, start, end time.Time, opts *ReconciliationOptions) ([]ReconciliationRecord, error) {
+	var options ReconciliationOptions
+	if opts != nil {
+		options = *opts
+	}
+	var interval = DefaultReconciliationPollInterval
+	if options.PollInterval > 0 {
+		interval = options.PollInterval
+	}
+
+	task, err := c.ApplyReconciliation(ctx, start, end, options.CallOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		switch task.Status {
+		case ReconciliationStatusSuccess:
+			return c.DownloadReconciliation(ctx, task.DownloadURL)
+		case ReconciliationStatusFailed:
+			return nil, fmt.Errorf("reconciliation task %s failed: %s", task.TaskID, task.Message)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		task, err = c.QueryReconciliation(ctx, task.TaskID, options.CallOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+var reconciliationColumns = map[string]string{
+	"order_id":          "order_id",
+	"订单号":               "order_id",
+	"customer_order_no": "customer_order_no",
+	"外部订单号":             "customer_order_no",
+	"product_id":        "product_id",
+	"商品编号":              "product_id",
+	"product_name":      "product_name",
+	"商品名称":              "product_name",
+	"buy_num":           "buy_num",
+	"购买数量":              "buy_num",
+	"amount":            "amount",
+	"order_price":       "amount",
+	"订单金额":              "amount",
+	"order_state":       "order_state",
+	"订单状态":              "order_state",
+	"create_time":       "create_time",
+	"创建时间":              "create_time",
+	"finish_time":       "finish_time",
+	"完成时间":              "finish_time",
+}
+
+var reconciliationStates = map[string]OrderState{
+	OrderStateSuccess:    OrderStateSuccess,
+	"成功":                 OrderStateSuccess,
+	OrderStateProcessing: OrderStateProcessing,
+	"处理中":                OrderStateProcessing,
+	OrderStateFailed:     OrderStateFailed,
+	"失败":                 OrderStateFailed,
+	OrderStateUntreated:  OrderStateUntreated,
+	"未处理":                OrderStateUntreated,
+}
+
+// ParseReconciliation 解析csv格式的对账单文件，首行为表头
+func ParseReconciliation(r io.Reader) ([]ReconciliationRecord, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && string(bom) == "\xef\xbb\xbf" {
+		_, _ = br.Discard(3)
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("reconciliation file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var columns = make(map[string]int, len(header))
+	for i, name := range header {
+		if column, ok := reconciliationColumns[strings.TrimSpace(name)]; ok {
+			columns[column] = i
+		}
+	}
+	for _, column := range []string{"order_id", "customer_order_no", "amount", "order_state"} {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("reconciliation file missing column %s", column)
+		}
+	}
+
+	var records []ReconciliationRecord
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(row) == 1 && strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+		record, err := parseReconciliationRow(row, columns)
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("reconciliation file line %d: %w", line, err)
+		}
+		records = append(records, *record)
+	}
+	return records, nil
+}
+
+func parseReconciliationRow(row []string, columns map[string]int) (*ReconciliationRecord, error) {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	var (
+		record = ReconciliationRecord{
+			OrderID:         field("order_id"),
+			CustomerOrderNO: field("customer_order_no"),
+			ProductName:     field("product_name"),
+		}
+		err error
+	)
+	if v := field("product_id"); v != "" {
+		record.ProductID, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid product_id %q", v)
+		}
+	}
+	if v := field("buy_num"); v != "" {
+		record.BuyNum, err = strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid buy_num %q", v)
+		}
+	}
+	record.Amount, err = strconv.ParseFloat(field("amount"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount %q", field("amount"))
+	}
+	state, ok := reconciliationStates[field("order_state")]
+	if !ok {
+		return nil, fmt.Errorf("invalid order_state %q", field("order_state"))
+	}
+	record.OrderState = state
+	if v := field("create_time"); v != "" {
+		record.CreateTime, err = time.ParseInLocation(TimestampFormat, v, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid create_time %q", v)
+		}
+	}
+	if v := field("finish_time"); v != "" {
+		record.FinishTime, err = time.ParseInLocation(TimestampFormat, v, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid finish_time %q", v)
+		}
+	}
+	return &record, nil
+}
@@ -0,0 +1,96 @@
+package fulu_gosdk_test
+
+import (
+	fulu "github.com/t2krew/fulu-gosdk"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func localTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	tm, err := time.ParseInLocation(fulu.TimestampFormat, value, time.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tm
+}
+
+func TestParseReconciliation(t *testing.T) {
+	tests := []struct {
+		file    string
+		want    func(t *testing.T) []fulu.ReconciliationRecord
+		wantErr string
+	}{
+		{
+			file: "reconciliation_en.csv",
+			want: func(t *testing.T) []fulu.ReconciliationRecord {
+				return []fulu.ReconciliationRecord{
+					{
+						OrderID: "F001", CustomerOrderNO: "C001", ProductID: 10000001, ProductName: "Tencent QQ Coin 10",
+						BuyNum: 1, Amount: 9.8, OrderState: fulu.OrderStateSuccess,
+						CreateTime: localTime(t, "2024-05-01 10:00:00"), FinishTime: localTime(t, "2024-05-01 10:00:05"),
+					},
+					{
+						OrderID: "F002", CustomerOrderNO: "C002", ProductID: 10000002, ProductName: "Mobile Top-up, 50",
+						BuyNum: 2, Amount: 99.6, OrderState: fulu.OrderStateFailed,
+						CreateTime: localTime(t, "2024-05-01 11:30:00"), FinishTime: localTime(t, "2024-05-01 11:31:00"),
+					},
+					{
+						OrderID: "F003", CustomerOrderNO: "C003", ProductID: 10000001, ProductName: "Tencent QQ Coin 10",
+						BuyNum: 1, Amount: 9.8, OrderState: fulu.OrderStateProcessing,
+						CreateTime: localTime(t, "2024-05-01 23:59:59"),
+					},
+				}
+			},
+		},
+		{
+			file: "reconciliation_zh_bom.csv",
+			want: func(t *testing.T) []fulu.ReconciliationRecord {
+				return []fulu.ReconciliationRecord{
+					{
+						OrderID: "F101", CustomerOrderNO: "C101", ProductID: 10000001, ProductName: "腾讯Q币10元",
+						BuyNum: 1, Amount: 9.8, OrderState: fulu.OrderStateSuccess,
+						CreateTime: localTime(t, "2024-05-02 09:00:00"), FinishTime: localTime(t, "2024-05-02 09:00:03"),
+					},
+					{
+						OrderID: "F102", CustomerOrderNO: "C102", ProductID: 10000003, ProductName: "话费充值100元",
+						BuyNum: 1, Amount: 99.5, OrderState: fulu.OrderStateFailed,
+						CreateTime: localTime(t, "2024-05-02 09:10:00"), FinishTime: localTime(t, "2024-05-02 09:12:00"),
+					},
+				}
+			},
+		},
+		{file: "reconciliation_bad_amount.csv", wantErr: `line 3: invalid amount "abc"`},
+		{file: "reconciliation_bad_state.csv", wantErr: `line 2: invalid order_state "refunded"`},
+		{file: "reconciliation_bad_time.csv", wantErr: `line 2: invalid create_time "2024/05/01 10:00"`},
+		{file: "reconciliation_missing_column.csv", wantErr: "missing column amount"},
+		{file: "reconciliation_empty.csv", wantErr: "reconciliation file is empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			records, err := fulu.ParseReconciliation(f)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseReconciliation() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseReconciliation() error = %v", err)
+			}
+			if want := tt.want(t); !reflect.DeepEqual(records, want) {
+				t.Fatalf("ParseReconciliation() = %+v, want %+v", records, want)
+			}
+		})
+	}
+}
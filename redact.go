@@ -0,0 +1,115 @@
+package fulu_gosdk
+
+import (
+	"regexp"
+	"strings"
+)
+
+const redactMask = "******"
+
+// RedactRule 脱敏规则，Mask接收匹配到的内容并返回替换后的内容
+type RedactRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	Mask    func(match string) string
+}
+
+// Redactor 日志脱敏器，并发安全
+type Redactor struct {
+	rules   []RedactRule
+	secrets []string
+}
+
+// NewRedactor 初始化脱敏器
+func NewRedactor(rules ...RedactRule) *Redactor {
+	return &Redactor{rules: append([]RedactRule{}, rules...)}
+}
+
+// WithSecrets 返回额外屏蔽指定明文的脱敏器，如AppSecret
+func (r *Redactor) WithSecrets(secrets ...string) *Redactor {
+	if r == nil {
+		r = &Redactor{}
+	}
+	var redactor = &Redactor{
+		rules:   r.rules,
+		secrets: append([]string{}, r.secrets...),
+	}
+	for _, secret := range secrets {
+		if secret != "" {
+			redactor.secrets = append(redactor.secrets, secret)
+		}
+	}
+	return redactor
+}
+
+// Redact 对内容脱敏
+func (r *Redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, redactMask)
+	}
+	for _, rule := range r.rules {
+		s = rule.Pattern.ReplaceAllStringFunc(s, rule.Mask)
+	}
+	return s
+}
+
+// JSONFieldRule 对json字段值脱敏，兼容biz_content等被转义的json字符串
+func JSONFieldRule(name string, mask func(value string) string, fields ...string) RedactRule {
+	var quoted = make([]string, 0, len(fields))
+	for _, field := range fields {
+		quoted = append(quoted, regexp.QuoteMeta(field))
+	}
+	pattern := regexp.MustCompile(`(\\*"(?:` + strings.Join(quoted, "|") + `)\\*"\s*:\s*)(?:(\\*")([^"\\]*)(\\*")|(\d+))`)
+	return RedactRule{
+		Name:    name,
+		Pattern: pattern,
+		Mask: func(match string) string {
+			sub := pattern.FindStringSubmatch(match)
+			if sub[2] != "" {
+				return sub[1] + sub[2] + mask(sub[3]) + sub[4]
+			}
+			return sub[1] + mask(sub[5])
+		},
+	}
+}
+
+// MaskAll 全部屏蔽
+func MaskAll(string) string {
+	return redactMask
+}
+
+// MaskMiddle 保留首尾字符，屏蔽中间部分
+func MaskMiddle(head, tail int) func(string) string {
+	return func(value string) string {
+		runes := []rune(value)
+		if len(runes) <= head+tail {
+			return redactMask
+		}
+		return string(runes[:head]) + strings.Repeat("*", len(runes)-head-tail) + string(runes[len(runes)-tail:])
+	}
+}
+
+var phonePattern = regexp.MustCompile(`(^|[^\d])(1[3-9]\d{9})($|[^\d])`)
+
+// PhoneRule 屏蔽文本中的手机号
+var PhoneRule = RedactRule{
+	Name:    "phone",
+	Pattern: phonePattern,
+	Mask: func(match string) string {
+		sub := phonePattern.FindStringSubmatch(match)
+		return sub[1] + MaskMiddle(3, 4)(sub[2]) + sub[3]
+	},
+}
+
+// DefaultRedactRules 默认脱敏规则：充值密码、卡密、手机号、QQ号
+func DefaultRedactRules() []RedactRule {
+	return []RedactRule{
+		JSONFieldRule("password", MaskAll, "charge_password", "card_pwd", "app_secret", "app_auth_token"),
+		JSONFieldRule("phone", MaskMiddle(3, 4), "charge_phone", "contact_tel", "phone", "mobile"),
+		JSONFieldRule("qq", MaskMiddle(2, 2), "contact_qq", "qq"),
+		PhoneRule,
+	}
+}
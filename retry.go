@@ -0,0 +1,76 @@
+package fulu_gosdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy 重试策略，仅对只读或幂等接口生效
+type RetryPolicy struct {
+	MaxAttempts     int     // 最大请求次数，含首次请求
+	Backoff         Backoff // 重试间隔
+	RetryableStatus []int   // 可重试的http状态码
+	RetryableCodes  []int   // 可重试的福禄返回码
+}
+
+// DefaultRetryPolicy 默认重试策略
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	Backoff: Backoff{
+		Initial:    200 * time.Millisecond,
+		Max:        2 * time.Second,
+		Multiplier: 2,
+		Jitter:     0.2,
+	},
+	RetryableStatus: []int{
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+	RetryableCodes: []int{CodeThrottled, CodeSystemBusy},
+}
+
+// NoRetry 不重试
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+func (p RetryPolicy) clone() RetryPolicy {
+	p.RetryableStatus = append([]int(nil), p.RetryableStatus...)
+	p.RetryableCodes = append([]int(nil), p.RetryableCodes...)
+	return p
+}
+
+func (p *RetryPolicy) attempts(method Method) int {
+	if info := method.Info(); p.MaxAttempts <= 1 || !info.ReadOnly && !info.Idempotent {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		// 网络错误
+		return true
+	}
+	if apiErr.Code != CodeSuccess {
+		for _, code := range p.RetryableCodes {
+			if apiErr.Code == code {
+				return true
+			}
+		}
+		return false
+	}
+	for _, status := range p.RetryableStatus {
+		if apiErr.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package fulu_gosdk_test
+
+import (
+	"context"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryByMethodInfo(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       fulu.Method
+		info         fulu.MethodInfo
+		wantAttempts int32
+	}{
+		{name: "read only", method: "test.retry.readonly", info: fulu.MethodInfo{ReadOnly: true}, wantAttempts: 3},
+		{name: "idempotent", method: "test.retry.idempotent", info: fulu.MethodInfo{Idempotent: true}, wantAttempts: 3},
+		{name: "write", method: "test.retry.write", info: fulu.MethodInfo{}, wantAttempts: 1},
+		{name: "create order", method: fulu.MethodCreateDirectOrder, info: fulu.MethodCreateDirectOrder.Info(), wantAttempts: 1},
+		{name: "query order", method: fulu.MethodQueryOrder, info: fulu.MethodQueryOrder.Info(), wantAttempts: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&hits, 1)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer srv.Close()
+
+			fulu.RegisterMethod(tt.method, tt.info)
+			client, err := fulu.New(fulu.Config{Endpoint: srv.URL, AppKey: testAppKey, AppSecret: testAppSecret}, fulu.WithRetryPolicy(fulu.RetryPolicy{
+				MaxAttempts:     3,
+				Backoff:         fulu.Backoff{Initial: time.Millisecond},
+				RetryableStatus: []int{http.StatusServiceUnavailable},
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = client.Request(context.Background(), tt.method, struct{}{}, nil); err == nil {
+				t.Fatal("Request() error = nil, want 503")
+			}
+			if got := atomic.LoadInt32(&hits); got != tt.wantAttempts {
+				t.Fatalf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package fulu_gosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CreateOutcome 安全下单结果
+type CreateOutcome string
+
+const (
+	CreateOutcomeCreated  = CreateOutcome("created")  // 本次提交创建成功
+	CreateOutcomeExisted  = CreateOutcome("existed")  // 订单已存在，未重复提交
+	CreateOutcomeRejected = CreateOutcome("rejected") // 福禄明确拒绝
+)
+
+// ErrOrderOutcomeUnknown 无法确认订单是否已创建
+var ErrOrderOutcomeUnknown = errors.New("fulu: order outcome unknown")
+
+// SafeCreateOptions 安全下单参数
+type SafeCreateOptions struct {
+	MaxAttempts    int           // 最大提交次数，默认3次
+	Backoff        Backoff       // 重试及补查的退避策略
+	AttemptTimeout time.Duration // 单次请求超时时间，0为使用ctx
+	CallOptions    []CallOption  // 下单及补查订单的调用配置
+}
+
+// SafeCreateResult 安全下单结果
+type SafeCreateResult struct {
+	Outcome  CreateOutcome `json:"outcome"`
+	Order    *Order        `json:"order"`
+	Attempts int           `json:"attempts"`
+}
+
+// SafeCreateDirectOrder 安全创建直充订单，结果不明确时先按外部订单号查单再决定是否重新提交
+func (c *Client) SafeCreateDirectOrder(ctx context.Context, params CreateDirectOrderBizContent, opts *SafeCreateOptions) (*SafeCreateResult, error) {
+	return c.safeCreate(ctx, params.CustomerOrder, opts, func(ctx context.Context, opts ...CallOption) (*Order, error) {
+		result, err := c.CreateDirectOrder(ctx, params, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return result.toOrder(), nil
+	})
+}
+
+// SafeCreateCardOrder 安全创建卡密订单，结果不明确时先按外部订单号查单再决定是否重新提交
+func (c *Client) SafeCreateCardOrder(ctx context.Context, params CreateCardOrderBizContent, opts *SafeCreateOptions) (*SafeCreateResult, error) {
+	return c.safeCreate(ctx, params.CustomerOrderNO, opts, func(ctx context.Context, opts ...CallOption) (*Order, error) {
+		result, err := c.CreateCardOrder(ctx, params, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return result.toOrder(), nil
+	})
+}
+
+// SafeCreateMobileOrder 安全创建话费订单，结果不明确时先按外部订单号查单再决定是否重新提交
+func (c *Client) SafeCreateMobileOrder(ctx context.Context, params CreateMobileOrderBizContent, opts *SafeCreateOptions) (*SafeCreateResult, error) {
+	return c.safeCreate(ctx, params.CustomerOrderNO, opts, func(ctx context.Context, opts ...CallOption) (*Order, error) {
+		result, err := c.CreateMobileOrder(ctx, params, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return result.toOrder(), nil
+	})
+}
+
+func (c *Client) safeCreate(ctx context.Context, customerOrderNO string, opts *SafeCreateOptions, create func(ctx context.Context, opts ...CallOption) (*Order, error)) (*SafeCreateResult, error) {
+	if customerOrderNO == "" {
+		return nil, errors.New("customer order no is empty")
+	}
+	var options = SafeCreateOptions{MaxAttempts: 3, Backoff: DefaultBackoff}
+	if opts != nil {
+		options = *opts
+		if options.MaxAttempts <= 0 {
+			options.MaxAttempts = 3
+		}
+	}
+
+	var (
+		result  = &SafeCreateResult{}
+		lastErr error
+		delay   int
+	)
+	for result.Attempts < options.MaxAttempts {
+		result.Attempts++
+		order, err := withAttemptTimeout(ctx, options.AttemptTimeout, func(ctx context.Context) (*Order, error) {
+			return create(ctx, options.CallOptions...)
+		})
+		switch {
+		case err == nil:
+			result.Outcome = CreateOutcomeCreated
+			result.Order = order
+			return result, nil
+		case errors.Is(err, ErrThrottled), errors.Is(err, ErrCircuitOpen):
+			// 被限流或熔断的请求未进入下单流程，可直接重新提交
+			lastErr = err
+			if err := sleepContext(ctx, options.Backoff.Delay(delay)); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrOrderOutcomeUnknown, lastErr)
+			}
+			delay++
+			continue
+		case !errors.Is(err, ErrDuplicateOrder) && !isAmbiguousCreateError(err):
+			result.Outcome = CreateOutcomeRejected
+			return result, err
+		}
+
+		lastErr = err
+		order, exists, err := c.resolveOrder(ctx, customerOrderNO, &options, &delay)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrOrderOutcomeUnknown, err)
+		}
+		if exists {
+			result.Outcome = CreateOutcomeExisted
+			result.Order = order
+			return result, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %v", ErrOrderOutcomeUnknown, lastErr)
+}
+
+// resolveOrder 按外部订单号查询订单是否已存在，查询失败或单次查询超时时按退避策略重试，
+// 只在调用方ctx结束时停止
+func (c *Client) resolveOrder(ctx context.Context, customerOrderNO string, options *SafeCreateOptions, delay *int) (*Order, bool, error) {
+	var lastErr error
+	for i := 0; i < options.MaxAttempts; i++ {
+		if err := sleepContext(ctx, options.Backoff.Delay(*delay)); err != nil {
+			return nil, false, err
+		}
+		*delay++
+
+		order, err := withAttemptTimeout(ctx, options.AttemptTimeout, func(ctx context.Context) (*Order, error) {
+			return c.QueryOrder(ctx, customerOrderNO, options.CallOptions...)
+		})
+		switch {
+		case err == nil:
+			return order, true, nil
+		case errors.Is(err, ErrOrderNotFound):
+			return nil, false, nil
+		case ctx.Err() != nil, !isTransientError(err):
+			return nil, false, err
+		}
+		lastErr = err
+	}
+	return nil, false, lastErr
+}
+
+// isAmbiguousCreateError 下单请求是否可能已被福禄受理
+func isAmbiguousCreateError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+	return apiErr.Code == CodeSuccess || errors.Is(err, ErrSystemBusy)
+}
+
+func withAttemptTimeout(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) (*Order, error)) (*Order, error) {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+func (r *DirectOrderResult) toOrder() *Order {
+	return &Order{
+		OrderID:              r.OrderID,
+		CustomerOrderNO:      r.CustomerOrderNO,
+		ProductID:            r.ProductID,
+		ProductName:          r.ProductName,
+		ChargeAccount:        r.ChargeAccount,
+		BuyNum:               r.BuyNum,
+		OrderPrice:           r.OrderPrice,
+		OrderType:            r.OrderType,
+		OrderState:           r.PrderState,
+		CreateTime:           r.CreateTime,
+		FinishTime:           r.FinishTime,
+		Area:                 r.Area,
+		Server:               r.Server,
+		Type:                 r.Type,
+		OperatorSerialNumber: r.OperatorSerialNumber,
+	}
+}
+
+func (r *CardOrderResult) toOrder() *Order {
+	return &Order{
+		OrderID:              r.OrderID,
+		CustomerOrderNO:      r.CustomerOrderNO,
+		ProductID:            r.ProductID,
+		ProductName:          r.ProductName,
+		BuyNum:               r.BuyNum,
+		OrderPrice:           r.OrderPrice,
+		OrderType:            r.OrderType,
+		OrderState:           r.OrderState,
+		CreateTime:           r.CreateTime,
+		FinishTime:           r.FinishTime,
+		OperatorSerialNumber: r.OperatorSerialNumber,
+	}
+}
+
+func (r *MobileOrderResult) toOrder() *Order {
+	return &Order{
+		OrderID:              r.OrderID,
+		CustomerOrderNO:      r.CustomerOrderNO,
+		ProductID:            r.ProductID,
+		ProductName:          r.ProductName,
+		ChargeAccount:        r.ChargeAccount,
+		BuyNum:               r.BuyNum,
+		OrderPrice:           r.OrderPrice,
+		OrderType:            r.OrderType,
+		OrderState:           r.OrderState,
+		CreateTime:           r.CreateTime,
+		FinishTime:           r.FinishTime,
+		OperatorSerialNumber: r.OperatorSerialNumber,
+	}
+}
@@ -0,0 +1,126 @@
+package fulu_gosdk_test
+
+import (
+	"context"
+	"errors"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"github.com/t2krew/fulu-gosdk/fulutest"
+	"testing"
+	"time"
+)
+
+func TestSafeCreateDirectOrder(t *testing.T) {
+	const customerOrderNO = "safe-001"
+	var fastBackoff = fulu.Backoff{Initial: 10 * time.Millisecond, Multiplier: 1}
+	tests := []struct {
+		name        string
+		setup       func(srv *fulutest.Server)
+		opts        *fulu.SafeCreateOptions
+		timeout     time.Duration
+		wantOutcome fulu.CreateOutcome
+		wantErr     error
+		wantCreates int
+	}{
+		{
+			name:        "created",
+			setup:       func(srv *fulutest.Server) {},
+			wantOutcome: fulu.CreateOutcomeCreated,
+			wantCreates: 1,
+		},
+		{
+			name: "response lost after processed",
+			setup: func(srv *fulutest.Server) {
+				srv.ScriptFaults(fulu.MethodCreateDirectOrder, fulutest.Fault{Kind: fulutest.FaultConnReset, Processed: true})
+			},
+			wantOutcome: fulu.CreateOutcomeExisted,
+			wantCreates: 1,
+		},
+		{
+			name: "slow query still resolves",
+			setup: func(srv *fulutest.Server) {
+				srv.ScriptFaults(fulu.MethodCreateDirectOrder, fulutest.Fault{Kind: fulutest.FaultConnReset, Processed: true})
+				srv.ScriptFaults(fulu.MethodQueryOrder, fulutest.Fault{Kind: fulutest.FaultLatency, Latency: 300 * time.Millisecond})
+			},
+			opts:        &fulu.SafeCreateOptions{Backoff: fastBackoff, AttemptTimeout: 100 * time.Millisecond},
+			wantOutcome: fulu.CreateOutcomeExisted,
+			wantCreates: 1,
+		},
+		{
+			name: "duplicate order",
+			setup: func(srv *fulutest.Server) {
+				srv.SeedOrders(fulu.Order{CustomerOrderNO: customerOrderNO, ProductID: 1001, OrderState: fulu.OrderStateProcessing})
+			},
+			wantOutcome: fulu.CreateOutcomeExisted,
+			wantCreates: 1,
+		},
+		{
+			name: "rejected",
+			setup: func(srv *fulutest.Server) {
+				srv.SetBalance(0)
+			},
+			wantOutcome: fulu.CreateOutcomeRejected,
+			wantErr:     fulu.ErrInsufficientBalance,
+			wantCreates: 1,
+		},
+		{
+			name: "caller deadline",
+			setup: func(srv *fulutest.Server) {
+				srv.ScriptFaults(fulu.MethodCreateDirectOrder, fulutest.Fault{Kind: fulutest.FaultConnReset, Processed: true})
+				srv.AddFaultRule(fulutest.FaultRule{Method: fulu.MethodQueryOrder, Probability: 1, Fault: fulutest.Fault{Kind: fulutest.FaultLatency, Latency: time.Second}})
+			},
+			opts:        &fulu.SafeCreateOptions{MaxAttempts: 10, Backoff: fastBackoff, AttemptTimeout: 100 * time.Millisecond},
+			timeout:     300 * time.Millisecond,
+			wantErr:     fulu.ErrOrderOutcomeUnknown,
+			wantCreates: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := fulutest.NewServer()
+			defer srv.Close()
+			srv.SetBalance(100)
+			srv.SeedProducts(fulu.ProductInfo{ProductID: 1001, ProductName: "游戏直充", PurchasePrice: 10})
+			tt.setup(srv)
+
+			client, err := srv.NewClient(fulu.WithRetryPolicy(fulu.NoRetry))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var opts = tt.opts
+			if opts == nil {
+				opts = &fulu.SafeCreateOptions{Backoff: fastBackoff}
+			}
+			var ctx = context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			result, err := client.SafeCreateDirectOrder(ctx, fulu.CreateDirectOrderBizContent{
+				ProductID:     1001,
+				CustomerOrder: customerOrderNO,
+				ChargeAccount: "player",
+				BuyNum:        1,
+			}, opts)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("SafeCreateDirectOrder() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantOutcome != "" {
+				if result == nil || result.Outcome != tt.wantOutcome {
+					t.Fatalf("SafeCreateDirectOrder() result = %+v, want outcome %q", result, tt.wantOutcome)
+				}
+				if tt.wantErr == nil && (result.Order == nil || result.Order.CustomerOrderNO != customerOrderNO) {
+					t.Fatalf("SafeCreateDirectOrder() order = %+v", result.Order)
+				}
+			}
+			if calls := srv.Calls(fulu.MethodCreateDirectOrder); len(calls) != tt.wantCreates {
+				t.Fatalf("create calls = %d, want %d", len(calls), tt.wantCreates)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package fulu_gosdk
+
+import (
+	"crypto/subtle"
+	"fmt"
+	jsoniter "github.com/json-iterator/go"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SignTypeMD5 福禄默认签名类型，其他签名类型需确认福禄账户的签名规则后通过RegisterSigner注册
+const SignTypeMD5 = "md5"
+
+// Signer 签名算法，payload为SignPayload生成的待签名字符串
+type Signer interface {
+	Sign(payload string) (string, error)
+	Verify(payload string, sign string) error
+}
+
+// SignerFactory 根据AppSecret创建签名器
+type SignerFactory func(secret string) (Signer, error)
+
+var (
+	signersMu sync.RWMutex
+	signers   = map[string]SignerFactory{
+		SignTypeMD5: func(secret string) (Signer, error) {
+			return &md5Signer{secret: secret}, nil
+		},
+	}
+)
+
+// RegisterSigner 注册签名类型，可覆盖内置实现
+func RegisterSigner(signType string, factory SignerFactory) {
+	signersMu.Lock()
+	defer signersMu.Unlock()
+	signers[strings.ToLower(signType)] = factory
+}
+
+// NewSigner 根据签名类型创建签名器
+func NewSigner(signType string, secret string) (Signer, error) {
+	signersMu.RLock()
+	factory, ok := signers[strings.ToLower(signType)]
+	signersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported sign type %q", signType)
+	}
+	return factory(secret)
+}
+
+var signJSON = jsoniter.Config{UseNumber: true}.Froze()
+
+// SignPayload 生成待签名字符串：序列化后去除sign字段，将所有字符排序后拼接。
+// v为[]byte时视为原始json
+func SignPayload(v interface{}) (string, error) {
+	raw, ok := v.([]byte)
+	if !ok {
+		var err error
+		raw, err = jsoniter.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+	}
+	signdata, err := decodeSigndata(raw)
+	if err != nil {
+		return "", err
+	}
+	return signdataPayload(signdata)
+}
+
+// VerifySign 校验福禄返回或推送内容中的sign字段
+func VerifySign(signer Signer, raw []byte) error {
+	signdata, err := decodeSigndata(raw)
+	if err != nil {
+		return err
+	}
+	sign, _ := signdata["sign"].(string)
+	if sign == "" {
+		return ErrSignMismatch
+	}
+	payload, err := signdataPayload(signdata)
+	if err != nil {
+		return err
+	}
+	return signer.Verify(payload, sign)
+}
+
+func decodeSigndata(raw []byte) (map[string]interface{}, error) {
+	var signdata map[string]interface{}
+	err := signJSON.Unmarshal(raw, &signdata)
+	if err != nil {
+		return nil, err
+	}
+	return signdata, nil
+}
+
+func signdataPayload(signdata map[string]interface{}) (string, error) {
+	var data = make(map[string]interface{}, len(signdata))
+	for k, v := range signdata {
+		if k != "sign" {
+			data[k] = v
+		}
+	}
+
+	serializeSigndata, err := jsoniter.MarshalToString(data)
+	if err != nil {
+		return "", err
+	}
+	var (
+		runeArray = []rune(serializeSigndata)
+		charArray = make([]string, 0, len(runeArray))
+	)
+	for _, char := range runeArray {
+		charArray = append(charArray, string(char))
+	}
+	sort.Strings(charArray)
+
+	return strings.Join(charArray, ""), nil
+}
+
+func equalSign(expected, sign string) error {
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(sign))) != 1 {
+		return ErrSignMismatch
+	}
+	return nil
+}
+
+// md5Signer 福禄默认签名：md5(payload + secret)
+type md5Signer struct {
+	secret string
+}
+
+func (s *md5Signer) Sign(payload string) (string, error) {
+	return strings.ToLower(MD5(payload + s.secret)), nil
+}
+
+func (s *md5Signer) Verify(payload string, sign string) error {
+	expected, _ := s.Sign(payload)
+	return equalSign(expected, sign)
+}
@@ -0,0 +1,188 @@
+package fulu_gosdk_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	jsoniter "github.com/json-iterator/go"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"github.com/t2krew/fulu-gosdk/fulutest"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testSignType = "test-hmac-sha256"
+
+// testHMACSigner 测试用的自定义签名器：hex(hmac-sha256(secret, payload))
+type testHMACSigner struct {
+	secret []byte
+}
+
+func (s *testHMACSigner) Sign(payload string) (string, error) {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func (s *testHMACSigner) Verify(payload string, sign string) error {
+	expected, _ := s.Sign(payload)
+	if !hmac.Equal([]byte(expected), []byte(sign)) {
+		return fulu.ErrSignMismatch
+	}
+	return nil
+}
+
+func init() {
+	fulu.RegisterSigner(testSignType, func(secret string) (fulu.Signer, error) {
+		return &testHMACSigner{secret: []byte(secret)}, nil
+	})
+}
+
+func TestNewSigner(t *testing.T) {
+	tests := []struct {
+		signType string
+		wantErr  bool
+	}{
+		{signType: fulu.SignTypeMD5},
+		{signType: "MD5"},
+		{signType: testSignType},
+		{signType: strings.ToUpper(testSignType)},
+		{signType: "hmac-sha256", wantErr: true},
+		{signType: "rsa", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.signType, func(t *testing.T) {
+			signer, err := fulu.NewSigner(tt.signType, testAppSecret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSigner() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && signer == nil {
+				t.Fatal("NewSigner() signer = nil")
+			}
+		})
+	}
+}
+
+func TestSignerRoundTrip(t *testing.T) {
+	var notification = fulu.OrderNotification{
+		OrderID:         "F001",
+		CustomerOrderNO: "C001",
+		OrderStatus:     fulu.OrderStateSuccess,
+		ProductID:       1001,
+		Price:           9.8,
+		BuyNum:          1,
+	}
+	for _, signType := range []string{fulu.SignTypeMD5, testSignType} {
+		t.Run(signType, func(t *testing.T) {
+			signer, err := fulu.NewSigner(signType, testAppSecret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			other, err := fulu.NewSigner(signType, "other-secret")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			payload, err := fulu.SignPayload(notification)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sign, err := signer.Sign(payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = signer.Verify(payload, sign); err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if err = other.Verify(payload, sign); !errors.Is(err, fulu.ErrSignMismatch) {
+				t.Fatalf("Verify() with other secret error = %v, want ErrSignMismatch", err)
+			}
+
+			var data map[string]interface{}
+			raw, _ := jsoniter.Marshal(notification)
+			if err = jsoniter.Unmarshal(raw, &data); err != nil {
+				t.Fatal(err)
+			}
+			data["sign"] = sign
+			body, _ := jsoniter.Marshal(data)
+			if err = fulu.VerifySign(signer, body); err != nil {
+				t.Fatalf("VerifySign() error = %v", err)
+			}
+
+			data["order_status"] = fulu.OrderStateFailed
+			tampered, _ := jsoniter.Marshal(data)
+			if err = fulu.VerifySign(signer, tampered); !errors.Is(err, fulu.ErrSignMismatch) {
+				t.Fatalf("VerifySign() tampered error = %v, want ErrSignMismatch", err)
+			}
+
+			delete(data, "sign")
+			unsigned, _ := jsoniter.Marshal(data)
+			if err = fulu.VerifySign(signer, unsigned); !errors.Is(err, fulu.ErrSignMismatch) {
+				t.Fatalf("VerifySign() unsigned error = %v, want ErrSignMismatch", err)
+			}
+		})
+	}
+}
+
+func TestRegisteredSignerOnWire(t *testing.T) {
+	srv := fulutest.NewServer(fulutest.WithSignType(testSignType))
+	defer srv.Close()
+	srv.SetBalance(100)
+
+	client, err := srv.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetAccountInfo(context.Background()); err != nil {
+		t.Fatalf("GetAccountInfo() error = %v", err)
+	}
+
+	md5Client, err := fulu.NewWithClient(fulu.Config{
+		Endpoint:   srv.URL,
+		AppKey:     fulutest.DefaultAppKey,
+		AppSecret:  fulutest.DefaultAppSecret,
+		SignType:   testSignType,
+		VerifySign: true,
+	}, http.DefaultClient, fulu.WithSigner(mustSigner(t, fulu.SignTypeMD5, fulutest.DefaultAppSecret)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = md5Client.GetAccountInfo(context.Background()); err == nil {
+		t.Fatal("GetAccountInfo() with mismatched signer error = nil")
+	}
+
+	var (
+		signer = mustSigner(t, testSignType, testAppSecret)
+		called bool
+	)
+	notify, err := fulu.New(fulu.Config{Endpoint: srv.URL, AppKey: testAppKey, AppSecret: testAppSecret, SignType: testSignType})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := notify.OrderNotifyHandler(func(ctx context.Context, n *fulu.OrderNotification) error {
+		called = true
+		return nil
+	})
+	payload, _ := fulu.SignPayload(map[string]interface{}{"customer_order_no": "C001", "order_status": "success"})
+	sign, _ := signer.Sign(payload)
+	body, _ := jsoniter.Marshal(map[string]interface{}{"customer_order_no": "C001", "order_status": "success", "sign": sign})
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/fulu/notify", bytes.NewReader(body)))
+	if w.Code != http.StatusOK || !called {
+		t.Fatalf("notify response = %d %q, called %v", w.Code, w.Body.String(), called)
+	}
+}
+
+func mustSigner(t *testing.T, signType, secret string) fulu.Signer {
+	t.Helper()
+	signer, err := fulu.NewSigner(signType, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
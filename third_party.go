@@ -23,14 +23,14 @@ type GetQQNicknameResult struct {
 }
 
 // GetQQNickname 获取qq昵称
-func (c *Client) GetQQNickname(ctx context.Context, qqNumber string) (*GetQQNicknameResult, error) {
+func (c *Client) GetQQNickname(ctx context.Context, qqNumber string, opts ...CallOption) (*GetQQNicknameResult, error) {
 	var (
 		result GetQQNicknameResult
 		params = map[string]string{
 			"qq": qqNumber,
 		}
 	)
-	err := c.Request(ctx, MethodGetQQNickname, params, &result)
+	err := c.Request(ctx, MethodGetQQNickname, params, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -51,7 +51,7 @@ type GetMobileInfoResult struct {
 	SpType    string    `json:"sp_type"`    // 运营商类型 1:移动 2:电信 3:联通
 }
 
-// GetMobileInfo 获取手机归属地
+// GetMobileInfo 获取手机归属地，单次调用配置通过ContextWithCallOptions传入
 func (c *Client) GetMobileInfo(ctx context.Context, mobileNO string, faceValue ...float64) (*GetMobileInfoResult, error) {
 	var (
 		result GetMobileInfoResult
@@ -86,7 +86,7 @@ type GetMobileMaintainStatusResult struct {
 }
 
 // GetMobileMaintainStatus 话费维护状态检查
-func (c *Client) GetMobileMaintainStatus(ctx context.Context, mobileNO string, faceValue int) (*GetMobileMaintainStatusResult, error) {
+func (c *Client) GetMobileMaintainStatus(ctx context.Context, mobileNO string, faceValue int, opts ...CallOption) (*GetMobileMaintainStatusResult, error) {
 	var (
 		result GetMobileMaintainStatusResult
 		params = GetMobileMaintainStatusReqParams{
@@ -94,7 +94,7 @@ func (c *Client) GetMobileMaintainStatus(ctx context.Context, mobileNO string, f
 			FaceValue: faceValue,
 		}
 	)
-	err := c.Request(ctx, MethodGetMobileMaintainStatus, params, &result)
+	err := c.Request(ctx, MethodGetMobileMaintainStatus, params, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
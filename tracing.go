@@ -0,0 +1,70 @@
+package fulu_gosdk
+
+import (
+	"context"
+	jsoniter "github.com/json-iterator/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"strconv"
+)
+
+const tracerName = "github.com/t2krew/fulu-gosdk"
+
+// 链路追踪属性
+const (
+	AttrAppKey          = attribute.Key("fulu.app_key")
+	AttrMethod          = attribute.Key("fulu.method")
+	AttrCustomerOrderNO = attribute.Key("fulu.customer_order_no")
+	AttrProductID       = attribute.Key("fulu.product_id")
+	AttrCode            = attribute.Key("fulu.code")
+	AttrAttempt         = attribute.Key("fulu.attempt")
+	AttrHTTPStatus      = attribute.Key("http.response.status_code")
+)
+
+// startCallSpan 每次Request创建一个以接口名称命名的span
+func (c *Client) startCallSpan(ctx context.Context, call *Call) (context.Context, trace.Span) {
+	var attrs = []attribute.KeyValue{
+		AttrAppKey.String(c.appkey),
+		AttrMethod.String(string(call.Method)),
+	}
+	if customerOrderNO := callCustomerOrderNO(call); customerOrderNO != "" {
+		attrs = append(attrs, AttrCustomerOrderNO.String(customerOrderNO))
+	}
+	if productID := callProductID(call); productID != "" {
+		attrs = append(attrs, AttrProductID.String(productID))
+	}
+	return c.tracer.Start(ctx, string(call.Method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// endSpan 记录调用结果并结束span
+func endSpan(span trace.Span, call *Call, err error) {
+	if call.StatusCode != 0 {
+		span.SetAttributes(AttrHTTPStatus.Int(call.StatusCode))
+	}
+	if call.Response != nil {
+		span.SetAttributes(AttrCode.Int(call.Response.Code))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func callProductID(call *Call) string {
+	if call.RawBizContent == "" {
+		return ""
+	}
+	v := jsoniter.Get([]byte(call.RawBizContent), "product_id")
+	switch v.ValueType() {
+	case jsoniter.StringValue:
+		return v.ToString()
+	case jsoniter.NumberValue:
+		return strconv.FormatInt(v.ToInt64(), 10)
+	}
+	return ""
+}
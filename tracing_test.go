@@ -0,0 +1,143 @@
+package fulu_gosdk_test
+
+import (
+	"context"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"github.com/t2krew/fulu-gosdk/fulutest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func spanAttr(span tracetest.SpanStub, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func childSpans(spans tracetest.SpanStubs, parent tracetest.SpanStub, name string) []tracetest.SpanStub {
+	var children []tracetest.SpanStub
+	for _, span := range spans {
+		if span.Parent.SpanID() == parent.SpanContext.SpanID() && span.Name == name {
+			children = append(children, span)
+		}
+	}
+	return children
+}
+
+func TestClientTracing(t *testing.T) {
+	srv := fulutest.NewServer()
+	defer srv.Close()
+	srv.SetBalance(100)
+	srv.SeedProducts(fulu.ProductInfo{ProductID: 1002, ProductName: "卡密", PurchasePrice: 10})
+	srv.ScriptFaults(fulu.MethodQueryOrder, fulutest.Fault{Kind: fulutest.FaultHTTPStatus, StatusCode: http.StatusServiceUnavailable})
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+
+	var policy = fulu.DefaultRetryPolicy
+	policy.Backoff = fulu.Backoff{Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 1}
+	client, err := srv.NewClient(fulu.WithTracerProvider(provider), fulu.WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err = client.CreateCardOrder(ctx, fulu.CreateCardOrderBizContent{ProductID: 1002, BuyNum: 1, CustomerOrderNO: "card-001"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.QueryOrder(ctx, "card-001"); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	tests := []struct {
+		method     fulu.Method
+		attrs      []attribute.KeyValue
+		attempts   int
+		failedOnce bool
+	}{
+		{
+			method: fulu.MethodCreateCardOrder,
+			attrs: []attribute.KeyValue{
+				fulu.AttrAppKey.String(fulutest.DefaultAppKey),
+				fulu.AttrMethod.String(string(fulu.MethodCreateCardOrder)),
+				fulu.AttrCustomerOrderNO.String("card-001"),
+				fulu.AttrProductID.String("1002"),
+				fulu.AttrCode.Int(fulu.CodeSuccess),
+				fulu.AttrHTTPStatus.Int(http.StatusOK),
+			},
+			attempts: 1,
+		},
+		{
+			method: fulu.MethodQueryOrder,
+			attrs: []attribute.KeyValue{
+				fulu.AttrAppKey.String(fulutest.DefaultAppKey),
+				fulu.AttrMethod.String(string(fulu.MethodQueryOrder)),
+				fulu.AttrCustomerOrderNO.String("card-001"),
+				fulu.AttrCode.Int(fulu.CodeSuccess),
+				fulu.AttrHTTPStatus.Int(http.StatusOK),
+			},
+			attempts:   2,
+			failedOnce: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			var root *tracetest.SpanStub
+			for i := range spans {
+				if spans[i].Name == string(tt.method) {
+					root = &spans[i]
+				}
+			}
+			if root == nil {
+				t.Fatalf("span %s not found in %d spans", tt.method, len(spans))
+			}
+			if root.Parent.IsValid() {
+				t.Errorf("span %s has parent, want root span", tt.method)
+			}
+			if root.SpanKind != trace.SpanKindClient {
+				t.Errorf("span kind = %v, want client", root.SpanKind)
+			}
+			if root.Status.Code == codes.Error {
+				t.Errorf("span status = %v, want not error", root.Status)
+			}
+			for _, want := range tt.attrs {
+				if got, ok := spanAttr(*root, want.Key); !ok || got != want.Value {
+					t.Errorf("attribute %s = %v, want %v", want.Key, got.Emit(), want.Value.Emit())
+				}
+			}
+
+			attempts := childSpans(spans, *root, "fulu.attempt")
+			if len(attempts) != tt.attempts {
+				t.Fatalf("fulu.attempt spans = %d, want %d", len(attempts), tt.attempts)
+			}
+			for i, attempt := range attempts {
+				if got, _ := spanAttr(attempt, fulu.AttrAttempt); got.AsInt64() != int64(i+1) {
+					t.Errorf("attempt span %d fulu.attempt = %d", i, got.AsInt64())
+				}
+				if signs := childSpans(spans, attempt, "fulu.sign"); len(signs) != 1 {
+					t.Errorf("attempt span %d fulu.sign children = %d, want 1", i, len(signs))
+				}
+			}
+			if tt.failedOnce {
+				first := attempts[0]
+				if first.Status.Code != codes.Error {
+					t.Errorf("first attempt status = %v, want error", first.Status)
+				}
+				if got, _ := spanAttr(first, fulu.AttrHTTPStatus); got.AsInt64() != http.StatusServiceUnavailable {
+					t.Errorf("first attempt http status = %d, want 503", got.AsInt64())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package fulu_gosdk
+
+import (
+	"context"
+	"time"
+)
+
+// WaitOptions 订单轮询参数
+type WaitOptions struct {
+	Backoff     Backoff // 轮询间隔退避策略
+	MaxAttempts int     // 最大查询次数，0为不限制
+	// CallOptions 每次查询订单的调用配置
+	CallOptions []CallOption
+}
+
+// OrderStateObservation 轮询时观察到的订单状态
+type OrderStateObservation struct {
+	State OrderState `json:"state"`
+	At    time.Time  `json:"at"`
+}
+
+// WaitResult 订单轮询结果
+type WaitResult struct {
+	Order   *Order                  `json:"order"`
+	History []OrderStateObservation `json:"history"`
+}
+
+// IsFinalOrderState 订单是否已是最终状态
+func IsFinalOrderState(state OrderState) bool {
+	return state == OrderStateSuccess || state == OrderStateFailed
+}
+
+// WaitForOrder 轮询订单直至成功或失败，查询失败、单次查询超时及订单暂不存在时继续轮询，
+// 调用方ctx结束或出错时仍返回已观察到的状态记录
+func (c *Client) WaitForOrder(ctx context.Context, customerOrderNO string, opts *WaitOptions) (*WaitResult, error) {
+	var options = WaitOptions{Backoff: DefaultBackoff}
+	if opts != nil {
+		options = *opts
+	}
+
+	var result = &WaitResult{}
+	for attempt := 0; ; attempt++ {
+		order, err := c.QueryOrder(ctx, customerOrderNO, options.CallOptions...)
+		switch {
+		case err == nil:
+			result.Order = order
+			result.History = append(result.History, OrderStateObservation{State: OrderState(order.OrderState), At: time.Now()})
+			if IsFinalOrderState(OrderState(order.OrderState)) {
+				c.orderFinished(customerOrderNO, order.ProductID, OrderState(order.OrderState))
+				return result, nil
+			}
+		case ctx.Err() != nil, !isTransientError(err):
+			return result, err
+		}
+
+		if options.MaxAttempts > 0 && attempt+1 >= options.MaxAttempts {
+			if err != nil {
+				return result, err
+			}
+			return result, ErrOrderNotFinished
+		}
+		if err := sleepContext(ctx, options.Backoff.Delay(attempt)); err != nil {
+			return result, err
+		}
+	}
+}
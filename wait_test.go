@@ -0,0 +1,148 @@
+package fulu_gosdk_test
+
+import (
+	"context"
+	"errors"
+	fulu "github.com/t2krew/fulu-gosdk"
+	"github.com/t2krew/fulu-gosdk/fulutest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWaitForOrder(t *testing.T) {
+	const customerOrderNO = "wait-001"
+	var fastBackoff = fulu.Backoff{Initial: 10 * time.Millisecond, Multiplier: 1}
+	tests := []struct {
+		name        string
+		setup       func(t *testing.T, srv *fulutest.Server, client *fulu.Client)
+		ctx         func() (context.Context, context.CancelFunc)
+		callOptions []fulu.CallOption
+		maxAttempts int
+		appearAfter int // 第几次查询后订单才存在，0为一开始就存在
+		wantErr     error
+		wantHistory []fulu.OrderState
+		wantQueries int
+	}{
+		{
+			name: "polls until success",
+			setup: func(t *testing.T, srv *fulutest.Server, client *fulu.Client) {
+				srv.SetOrderLifecycle(customerOrderNO, fulutest.Lifecycle{Polls: 2})
+				createOrder(t, client, customerOrderNO)
+			},
+			wantHistory: []fulu.OrderState{fulu.OrderStateProcessing, fulu.OrderStateProcessing, fulu.OrderStateSuccess},
+			wantQueries: 3,
+		},
+		{
+			name: "not found before order appears",
+			setup: func(t *testing.T, srv *fulutest.Server, client *fulu.Client) {
+				srv.SetOrderLifecycle(customerOrderNO, fulutest.Lifecycle{State: fulu.OrderStateFailed})
+			},
+			appearAfter: 2,
+			wantHistory: []fulu.OrderState{fulu.OrderStateFailed},
+			wantQueries: 3,
+		},
+		{
+			name: "max attempts",
+			setup: func(t *testing.T, srv *fulutest.Server, client *fulu.Client) {
+				srv.SetOrderLifecycle(customerOrderNO, fulutest.Lifecycle{State: fulu.OrderStateProcessing})
+				createOrder(t, client, customerOrderNO)
+			},
+			maxAttempts: 3,
+			wantErr:     fulu.ErrOrderNotFinished,
+			wantHistory: []fulu.OrderState{fulu.OrderStateProcessing, fulu.OrderStateProcessing, fulu.OrderStateProcessing},
+			wantQueries: 3,
+		},
+		{
+			name: "query timeout then success",
+			setup: func(t *testing.T, srv *fulutest.Server, client *fulu.Client) {
+				createOrder(t, client, customerOrderNO)
+				srv.ScriptFaults(fulu.MethodQueryOrder, fulutest.Fault{Kind: fulutest.FaultLatency, Latency: 300 * time.Millisecond})
+			},
+			callOptions: []fulu.CallOption{fulu.WithTimeout(100 * time.Millisecond)},
+			wantHistory: []fulu.OrderState{fulu.OrderStateSuccess},
+			wantQueries: 2,
+		},
+		{
+			name: "caller deadline",
+			setup: func(t *testing.T, srv *fulutest.Server, client *fulu.Client) {
+				createOrder(t, client, customerOrderNO)
+				srv.ScriptFaults(fulu.MethodQueryOrder, fulutest.Fault{Kind: fulutest.FaultLatency, Latency: time.Second})
+			},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 100*time.Millisecond)
+			},
+			wantErr:     context.DeadlineExceeded,
+			wantQueries: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := fulutest.NewServer()
+			defer srv.Close()
+			srv.SetBalance(100)
+			srv.SeedProducts(fulu.ProductInfo{ProductID: 1001, ProductName: "游戏直充", PurchasePrice: 10})
+
+			var (
+				queries int
+				client  *fulu.Client
+				err     error
+			)
+			client, err = srv.NewClient(
+				fulu.WithRetryPolicy(fulu.NoRetry),
+				fulu.WithInterceptors(func(ctx context.Context, call *fulu.Call, next fulu.Invoker) error {
+					err := next(ctx, call)
+					if call.Method == fulu.MethodQueryOrder {
+						queries++
+						if queries == tt.appearAfter {
+							createOrder(t, client, customerOrderNO)
+						}
+					}
+					return err
+				}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.setup(t, srv, client)
+
+			var ctx, cancel = context.Background(), context.CancelFunc(func() {})
+			if tt.ctx != nil {
+				ctx, cancel = tt.ctx()
+			}
+			defer cancel()
+
+			result, err := client.WaitForOrder(ctx, customerOrderNO, &fulu.WaitOptions{Backoff: fastBackoff, MaxAttempts: tt.maxAttempts, CallOptions: tt.callOptions})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("WaitForOrder() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			var history []fulu.OrderState
+			for _, observation := range result.History {
+				history = append(history, observation.State)
+			}
+			if !reflect.DeepEqual(history, tt.wantHistory) {
+				t.Fatalf("WaitForOrder() history = %v, want %v", history, tt.wantHistory)
+			}
+			if queries != tt.wantQueries {
+				t.Fatalf("QueryOrder calls = %d, want %d", queries, tt.wantQueries)
+			}
+		})
+	}
+}
+
+func createOrder(t *testing.T, client *fulu.Client, customerOrderNO string) {
+	t.Helper()
+	_, err := client.CreateDirectOrder(context.Background(), fulu.CreateDirectOrderBizContent{
+		ProductID:     1001,
+		CustomerOrder: customerOrderNO,
+		ChargeAccount: "player",
+		BuyNum:        1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}